@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/cespare/xxhash"
 	"github.com/dgraph-io/badger/v3"
+	gethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	abcitypes "github.com/tendermint/tendermint/abci/types"
 	tmlog "github.com/tendermint/tendermint/libs/log"
@@ -39,13 +41,21 @@ const (
 	DefaultValueLength   = 1024
 	MaxQueryResultLength = 1024 * 1024
 	MaxQueryResultCount  = 255
+	ScanCheckInterval    = 256 // the keys visited between the checks of the query context
 
-	MainKeyHeadByte = byte(220)
+	MainKeyHeadByte     = byte(220)
+	MigratedKeyHeadByte = byte(221)
+	ShardMapKeyByte     = byte(222)
+
+	MigratedKeyLen = 1 + 4 + 8
 
 	CheckTxCodeOK                            = uint32(000)
 	CheckTxCodeErrorInvalidTxBytes           = uint32(001)
 	CheckTxCodeErrorInvalidBulletin          = uint32(002)
 	CheckTxCodeErrorInvalidStochasticPayment = uint32(003)
+	CheckTxCodeErrorUnderpriced              = uint32(004)
+	CheckTxCodeErrorPaymentDue               = uint32(005)
+	CheckTxCodeErrorTokenNotAccepted         = uint32(006)
 	CheckTxCodeError                         = uint32(99)
 
 	DeliverTxCodeErrorTimestampTooLong        = uint32(100)
@@ -66,21 +76,51 @@ type GanyApp interface {
 	GetGanyTxByUrl(ganyUrlBz []byte) (pb.GanyTx, error)
 	QueryBulletinByTimePeriod(typ pb.Bulletin_BulletinType, topicHash [32]byte, startTime, endTime int64,
 		excludeSNs map[string]struct{}) ([]*pb.Bulletin, error)
+	QueryBulletins(ctx context.Context, filter *BulletinFilter) ([]*pb.Bulletin, error)
+	GetShardMap() (*ShardMap, error)
+	GetAcceptedToken(token gethcmn.Address) (*AcceptedToken, error)
+	GetAcceptedTokens() []*AcceptedToken
+	GetMinDueTimeWindow() time.Duration
+	SetOverwriteListener(listener OverwriteListener)
+}
+
+// OverwriteListener is called after a block is committed, with the gany URLs of the bulletins
+// which are updated or deleted in the block
+type OverwriteListener func(ganyUrls [][]byte)
+
+// SbchClock gives the time of the latest followed smartBCH block, it returns 0 before any block is followed
+type SbchClock interface {
+	GetLatestBlockTime() int64
+}
+
+// BulletinFilter is used to query the bulletins which are not keyed by topic
+type BulletinFilter struct {
+	Types     []pb.Bulletin_BulletinType // empty means all types
+	From      []byte                     // empty means all authors
+	StartTime int64
+	EndTime   int64
+	Keyword   []byte // empty means no content search
 }
 
 var _ GanyApp = &GanyApplication{}
 
 // Bulletin: Type1||TopicHashXX8||Timestamp5||SN8||FromHashXX8 => Topic32||HistoryCount4||IdList||Bulletin
 // KeyMap: 220||BlockTime5||TxIndex3[1:3] => [Type1||TopicHashXX8||Timestamp5]...
+// MigratedKey: 221||TopicHash4||SN8 => Type1||TopicHashXX8||Timestamp5
+// ShardMap: 222||Version4 => json(ShardMap)
 // SN8: BlockTime5||TxIndex3
 // Gany URL: gany://TopicHash4hex.BlockTime5decimal.TxIndex3decimal (hex string)
 
 // GanyTxBz: (TxFieldLengths raw-bytes)16||StochasticPayment||Bulletin||AuthProof||AuthChallenge
 
 type GanyApplication struct {
-	db       *badger.DB
-	chainId  string
-	tmClient *tmhttp.HTTP
+	db        *badger.DB
+	chainId   string
+	tmClient  *tmhttp.HTTP
+	config    *Config
+	sbchClock SbchClock // nil if smartBCH is not followed
+
+	overwriteListener OverwriteListener
 
 	// logger
 	logger tmlog.Logger
@@ -90,18 +130,21 @@ type GanyApplication struct {
 	currentHeight         int64
 	currentTxIndex        int64
 	currentBlockTimestamp int64 // second
+	overwrittenUrls       [][]byte
 }
 
-func NewGanyApplication(db *badger.DB, tmPort string, logger tmlog.Logger) *GanyApplication {
+func NewGanyApplication(db *badger.DB, tmPort string, config *Config, sbchClock SbchClock, logger tmlog.Logger) *GanyApplication {
 	tmClient, err := tmhttp.New(fmt.Sprintf("http://127.0.0.1:%v", tmPort))
 	if err != nil {
 		panic(err)
 	}
 
 	return &GanyApplication{
-		db:       db,
-		tmClient: tmClient,
-		logger:   logger,
+		db:        db,
+		tmClient:  tmClient,
+		config:    config,
+		sbchClock: sbchClock,
+		logger:    logger,
 	}
 }
 
@@ -141,6 +184,19 @@ func (app *GanyApplication) CheckTx(req abcitypes.RequestCheckTx) abcitypes.Resp
 		return abcitypes.ResponseCheckTx{Code: code, Log: err.Error()}
 	}
 
+	if err = app.checkPayment(req.Tx); err != nil {
+		code := CheckTxCodeErrorUnderpriced
+		if err == ErrTokenNotAccepted {
+			code = CheckTxCodeErrorTokenNotAccepted
+		}
+		return abcitypes.ResponseCheckTx{Code: code, Log: err.Error()}
+	}
+
+	// only checked in CheckTx, DeliverTx must not depend on the local clock
+	if err = app.checkDueTime(req.Tx); err != nil {
+		return abcitypes.ResponseCheckTx{Code: CheckTxCodeErrorPaymentDue, Log: err.Error()}
+	}
+
 	return abcitypes.ResponseCheckTx{Code: CheckTxCodeOK}
 }
 
@@ -200,6 +256,10 @@ func (app *GanyApplication) DeliverTx(req abcitypes.RequestDeliverTx) abcitypes.
 		return abcitypes.ResponseDeliverTx{Code: code, Log: err.Error()}
 	}
 
+	if b, _ := pb.GanyTx(req.Tx).GetBulletin(); len(b.GetOldSn()) != 0 {
+		app.overwrittenUrls = append(app.overwrittenUrls, overwrittenUrlOf(b))
+	}
+
 	app.currentTxIndex++
 	return abcitypes.ResponseDeliverTx{Code: CheckTxCodeOK}
 }
@@ -210,6 +270,12 @@ func (app *GanyApplication) EndBlock(req abcitypes.RequestEndBlock) abcitypes.Re
 
 func (app *GanyApplication) Commit() abcitypes.ResponseCommit {
 	app.currentBatch.Commit()
+
+	// notify after the commit, so the listener never reads the old versions again
+	if len(app.overwrittenUrls) != 0 && app.overwriteListener != nil {
+		app.overwriteListener(app.overwrittenUrls)
+	}
+	app.overwrittenUrls = nil
 	return abcitypes.ResponseCommit{Data: []byte{}}
 }
 
@@ -275,38 +341,122 @@ func (app *GanyApplication) QueryBulletinByTimePeriod(typ pb.Bulletin_BulletinTy
 	return results, nil
 }
 
+// QueryBulletins scans the bulletins of all the topics in the time window, so the window should be narrow.
+// It returns ctx.Err() if ctx is done before the scan finishes.
+func (app *GanyApplication) QueryBulletins(ctx context.Context, filter *BulletinFilter) ([]*pb.Bulletin, error) {
+	var results []*pb.Bulletin
+	var err error
+
+	txErr := app.db.View(func(txn *badger.Txn) error {
+		results, err = scanBulletins(ctx, txn, filter)
+		return err
+	})
+
+	if txErr != nil {
+		return nil, txErr
+	}
+	return results, nil
+}
+
+// GetShardMap returns nil if no shard map is recorded in this shard
+func (app *GanyApplication) GetShardMap() (*ShardMap, error) {
+	var m *ShardMap
+	var err error
+
+	txErr := app.db.View(func(txn *badger.Txn) error {
+		m, err = getLatestShardMap(txn)
+		return err
+	})
+
+	if txErr != nil {
+		return nil, txErr
+	}
+	return m, nil
+}
+
+func (app *GanyApplication) GetAcceptedToken(token gethcmn.Address) (*AcceptedToken, error) {
+	return app.config.AcceptedTokenOf(token)
+}
+
+func (app *GanyApplication) GetAcceptedTokens() []*AcceptedToken {
+	return app.config.AcceptedTokens()
+}
+
+// SetOverwriteListener must be called before the blocks are delivered
+func (app *GanyApplication) SetOverwriteListener(listener OverwriteListener) {
+	app.overwriteListener = listener
+}
+
+func (app *GanyApplication) GetMinDueTimeWindow() time.Duration {
+	return app.config.MinDueTimeWindow
+}
+
 // ---------------------------------Data------------------------------------------
 
+// checkPayment checks the tx against the pricing policy
+func (app *GanyApplication) checkPayment(ganyTx pb.GanyTx) error {
+	sp, err := ganyTx.GetStochasticPayment()
+	if err != nil {
+		return err
+	}
+	token, err := app.config.AcceptedTokenOf(TokenOf(sp))
+	if err != nil {
+		return err
+	}
+	if token.Pricing.IsFree() {
+		return nil
+	}
+
+	b, err := ganyTx.GetBulletin()
+	if err != nil {
+		return err
+	}
+	return token.Pricing.CheckPayment(sp, b)
+}
+
+func (app *GanyApplication) checkDueTime(ganyTx pb.GanyTx) error {
+	sp, err := ganyTx.GetStochasticPayment()
+	if err != nil {
+		return err
+	}
+
+	var blockTime int64
+	if app.sbchClock != nil {
+		blockTime = app.sbchClock.GetLatestBlockTime()
+	}
+	if blockTime == 0 {
+		blockTime = time.Now().Unix()
+	}
+	return CheckDueTime(sp, blockTime, app.config.MinDueTimeWindow)
+}
+
+// CheckDueTime returns ErrPaymentDue if the payment is due within `window` after the smartBCH block time,
+// the settlement would revert, and the bulletin must not be committed without a payment.
+func CheckDueTime(sp *pb.StochasticPayment, blockTime int64, window time.Duration) error {
+	if sp.GetDueTime() <= blockTime+int64(window/time.Second) {
+		return ErrPaymentDue
+	}
+	return nil
+}
+
 func validateGanyTxBz(ganyTx pb.GanyTx) (bool, error) {
 	return ganyTx.IsValid()
 }
 
 // Given GanyURL(TopicHash4||BlockTime5||TxIndex3), return the bulletin
 func getGanyTx(txn *badger.Txn, ganyUrlBz []byte) (ganyTx pb.GanyTx, err error) {
-	// lookup mainKeyHead range
-	key := append([]byte{MainKeyHeadByte}, ganyUrlBz[4:11]...)
-	item, err := txn.Get(key)
-	if err == badger.ErrKeyNotFound {
-		return nil, ErrKeyNotFound
-	} else if err != nil {
-		return nil, err
+	// lookup mainKeyHead of the bulletins moved from other shards first
+	mainKeyHead, err := getMigratedMainKeyHead(txn, ganyUrlBz)
+	if err == ErrKeyNotFound {
+		mainKeyHead, err = lookupMainKeyHead(txn, ganyUrlBz)
 	}
-
-	// lookup mainKeyHead
-	txIndex := int(ganyUrlBz[11])
-	mainKey := make([]byte, MainKeyLen)
-	err = item.Value(func(value []byte) error {
-		mainKeyHead := lookupMainKeyFromRange(value, txIndex)
-		if len(mainKeyHead) == 0 {
-			return ErrMainKeyHeadNotFound
-		}
-		copy(mainKey[:MainKeyHeadLen], mainKeyHead)
-		return nil
-	})
 	if err != nil {
 		return nil, err
 	}
 
+	mainKey := make([]byte, MainKeyLen)
+	copy(mainKey[:MainKeyHeadLen], mainKeyHead)
+
 	// lookup bulletin
 	copy(mainKey[MainKeyHeadLen:], ganyUrlBz[4:])
 	iter := txn.NewIterator(badger.DefaultIteratorOptions)
@@ -329,6 +479,41 @@ func getGanyTx(txn *badger.Txn, ganyUrlBz []byte) (ganyTx pb.GanyTx, err error)
 	return ganyTx, err
 }
 
+// overwrittenUrlOf returns the gany URL of the bulletin which is overwritten by b
+func overwrittenUrlOf(b *pb.Bulletin) []byte {
+	topicHash := b.GetTopicHash()
+	ganyUrl := make([]byte, 0, 4+8)
+	ganyUrl = append(ganyUrl, topicHash[:4]...)
+	return append(ganyUrl, b.GetOldSn()...)
+}
+
+func lookupMainKeyHead(txn *badger.Txn, ganyUrlBz []byte) ([]byte, error) {
+	// lookup mainKeyHead range
+	key := append([]byte{MainKeyHeadByte}, ganyUrlBz[4:11]...)
+	item, err := txn.Get(key)
+	if err == badger.ErrKeyNotFound {
+		return nil, ErrKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	// lookup mainKeyHead
+	txIndex := int(ganyUrlBz[11])
+	mainKeyHead := make([]byte, MainKeyHeadLen)
+	err = item.Value(func(value []byte) error {
+		head := lookupMainKeyFromRange(value, txIndex)
+		if len(head) == 0 {
+			return ErrMainKeyHeadNotFound
+		}
+		copy(mainKeyHead, head)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mainKeyHead, nil
+}
+
 func putGanyTx(txn *badger.Txn, ganyTx pb.GanyTx, blockTimestamp, txIndex int64) (err error) {
 	bulletin, err := ganyTx.GetBulletin()
 	if err != nil {
@@ -585,6 +770,135 @@ func queryBulletins(txn *badger.Txn, typ byte, topicHash [32]byte, startTime, en
 	return result, num, nil
 }
 
+// Get the bulletins matching the filter, between [startTime, endTime], in descending order of timestamp.
+// The keys of a topic are sorted by timestamp, so the scan seeks over the bulletins out of the time window
+// topic by topic. Only the latest MaxQueryResultCount bulletins are kept, and the scan stops when ctx is done.
+func scanBulletins(ctx context.Context, txn *badger.Txn, filter *BulletinFilter) ([]*pb.Bulletin, error) {
+	types := make(map[byte]struct{}, len(filter.Types))
+	for _, typ := range filter.Types {
+		types[byte(typ)] = struct{}{}
+	}
+
+	var stBuf [8]byte
+	var etBuf [8]byte
+	binary.BigEndian.PutUint64(stBuf[:], uint64(filter.StartTime))
+	binary.BigEndian.PutUint64(etBuf[:], uint64(filter.EndTime))
+	seekKey := func(topicPrefix []byte) []byte {
+		return append(append(make([]byte, 0, MainKeyHeadLen), topicPrefix...), stBuf[3:]...)
+	}
+
+	var fromHash []byte
+	if len(filter.From) != 0 {
+		fromHash = sum64(filter.From)[:8]
+	}
+
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchValues = false
+	iter := txn.NewIterator(opts)
+	defer iter.Close()
+
+	result := make([]*pb.Bulletin, 0, DefaultValueLength)
+	visited := 0
+	for typ := byte(pb.Bulletin_COMMENT); typ <= byte(pb.Bulletin_CENSOR); typ++ {
+		if _, ok := types[typ]; len(types) != 0 && !ok {
+			continue
+		}
+		// from the first topic of this type
+		iter.Seek(seekKey(append([]byte{typ}, make([]byte, 8)...)))
+		for iter.Valid() {
+			if visited++; visited%ScanCheckInterval == 0 && ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			item := iter.Item()
+			key := item.Key()
+			if key[0] != typ {
+				break
+			}
+			if len(key) != MainKeyLen {
+				iter.Next()
+				continue
+			}
+			if bytes.Compare(key[9:14], stBuf[3:]) < 0 {
+				iter.Seek(seekKey(key[:9]))
+				continue
+			}
+			if bytes.Compare(key[9:14], etBuf[3:]) > 0 {
+				next, ok := nextTopicPrefix(key[:9])
+				if !ok {
+					break
+				}
+				iter.Seek(seekKey(next))
+				continue
+			}
+			if fromHash != nil && !bytes.Equal(key[MainKeyHeadLen+8:], fromHash) {
+				iter.Next()
+				continue
+			}
+
+			err := item.Value(func(value []byte) error {
+				count := int(binary.BigEndian.Uint32(value[TopicHashEnd:HistoryCountEnd]))
+				txStart := HistoryCountEnd + count*BulletinIdLen
+				b, err := pb.GanyTx(value[txStart:]).GetBulletin()
+				if err != nil {
+					return err
+				}
+				if filter.matches(b) {
+					result = append(result, b)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(result) >= 2*MaxQueryResultCount {
+				result = latestBulletins(result)
+			}
+			iter.Next()
+		}
+	}
+	return latestBulletins(result), nil
+}
+
+// latestBulletins sorts the bulletins in descending order of timestamp, and keeps the first MaxQueryResultCount
+func latestBulletins(bulletins []*pb.Bulletin) []*pb.Bulletin {
+	sort.SliceStable(bulletins, func(i, j int) bool {
+		return bulletins[i].Timestamp > bulletins[j].Timestamp
+	})
+	if len(bulletins) > MaxQueryResultCount {
+		bulletins = bulletins[:MaxQueryResultCount]
+	}
+	return bulletins
+}
+
+// nextTopicPrefix returns the Type1||TopicHashXX8 after `prefix` in the same type
+func nextTopicPrefix(prefix []byte) ([]byte, bool) {
+	next := append([]byte{}, prefix...)
+	for i := len(next) - 1; i > 0; i-- {
+		if next[i]++; next[i] != 0 {
+			return next, true
+		}
+	}
+	return nil, false
+}
+
+func (filter *BulletinFilter) matches(b *pb.Bulletin) bool {
+	if len(filter.From) != 0 && !bytes.Equal(b.From, filter.From) {
+		return false
+	}
+	if len(filter.Keyword) == 0 {
+		return true
+	}
+	if bytes.Contains([]byte(b.ContentType), filter.Keyword) {
+		return true
+	}
+	for _, content := range b.ContentList {
+		if bytes.Contains(content, filter.Keyword) {
+			return true
+		}
+	}
+	return false
+}
+
 // ----------------------------------------------------------------
 
 func sum64(bz []byte) []byte {
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"encoding/binary"
 	"os"
 	"testing"
@@ -27,6 +28,7 @@ var (
 	TimestampNow       = TimeNow.Unix()
 	TimestampDuration  = TimeNow.Add(time.Hour).Unix()
 	TimestampTomorrow  = uint64(TimeNow.Add(time.Hour * 24).Unix())
+	TimestampDueTime   = time.Now().Add(time.Hour).Unix() // of the payments, which CheckTx compares with the clock
 
 	TimestampBlockOne = TimeNow.Add(time.Second * 10).Unix()
 	TimestampBlockTwo = TimeNow.Add(time.Second * 20).Unix()
@@ -307,6 +309,9 @@ func TestGetBulletinByGanyUrl(t *testing.T) {
 	})
 	require.NoError(t, txErr)
 
+	// the overwrite keeps the gany URL, which is sent to the overwrite listener
+	require.Equal(t, ganyUrlBz, overwrittenUrlOf(b2))
+
 	txErr = db.View(func(txn *badger.Txn) error {
 		txFromDB, err := getGanyTx(txn, ganyUrlBz)
 		require.NoError(t, err)
@@ -497,6 +502,130 @@ func TestQueryBulletinsWithExcludeSNs(t *testing.T) {
 	require.NoError(t, txErr)
 }
 
+func TestScanBulletins(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions(TestDataDir))
+	require.NoError(t, err)
+	defer cleanData(db)
+
+	b1 := &pb.Bulletin{
+		Type:        pb.Bulletin_BLOG,
+		Topic:       []byte{0x12},
+		Timestamp:   TimestampNow,
+		Duration:    TimestampDuration,
+		From:        TestAddress.Bytes(),
+		ContentType: "My Blog",
+		ContentList: [][]byte{
+			[]byte("hello gany"),
+		},
+	}
+
+	b2 := &pb.Bulletin{
+		Type:        pb.Bulletin_COMMENT,
+		Topic:       []byte{0x34},
+		Timestamp:   TimestampNow + 1,
+		Duration:    TimestampDuration,
+		From:        WrongAddress.Bytes(),
+		ContentType: "My Comment",
+		ContentList: [][]byte{
+			[]byte("hello world"),
+		},
+	}
+
+	txErr := db.Update(func(txn *badger.Txn) error {
+		err = putGanyTx(txn, pb.CreateGanyTx(nil, b1, nil, nil), TimestampBlockOne, 0)
+		require.NoError(t, err)
+		err = putGanyTx(txn, pb.CreateGanyTx(nil, b2, nil, nil), TimestampBlockOne, 1)
+		require.NoError(t, err)
+		return nil
+	})
+	require.NoError(t, txErr)
+
+	startTime := int64(TimestampYesterday)
+	endTime := int64(TimestampTomorrow)
+	txErr = db.View(func(txn *badger.Txn) error {
+		results, err := scanBulletins(context.Background(), txn, &BulletinFilter{StartTime: startTime, EndTime: endTime})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.EqualValues(t, b2.Timestamp, results[0].Timestamp)
+
+		results, err = scanBulletins(context.Background(), txn, &BulletinFilter{From: TestAddress.Bytes(), StartTime: startTime, EndTime: endTime})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.EqualValues(t, b1.From, results[0].From)
+
+		results, err = scanBulletins(context.Background(), txn, &BulletinFilter{Types: []pb.Bulletin_BulletinType{pb.Bulletin_COMMENT},
+			StartTime: startTime, EndTime: endTime})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.EqualValues(t, pb.Bulletin_COMMENT, results[0].Type)
+
+		results, err = scanBulletins(context.Background(), txn, &BulletinFilter{Keyword: []byte("gany"), StartTime: startTime, EndTime: endTime})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.EqualValues(t, b1.ContentList, results[0].ContentList)
+
+		results, err = scanBulletins(context.Background(), txn, &BulletinFilter{StartTime: startTime, EndTime: TimestampNow - 1})
+		require.NoError(t, err)
+		require.Len(t, results, 0)
+		return nil
+	})
+	require.NoError(t, txErr)
+}
+
+func TestScanBulletinsBounded(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions(TestDataDir))
+	require.NoError(t, err)
+	defer cleanData(db)
+
+	const n = 2*MaxQueryResultCount + 10
+	txErr := db.Update(func(txn *badger.Txn) error {
+		for i := 0; i < n; i++ {
+			b := &pb.Bulletin{
+				Type:        pb.Bulletin_COMMENT,
+				Topic:       []byte{byte(i), byte(i >> 8)},
+				Timestamp:   TimestampNow + int64(i),
+				Duration:    TimestampDuration,
+				From:        TestAddress.Bytes(),
+				ContentType: "My Comment",
+				ContentList: [][]byte{{byte(i)}},
+			}
+			require.NoError(t, putGanyTx(txn, pb.CreateGanyTx(nil, b, nil, nil), TimestampBlockOne, int64(i)))
+		}
+		return nil
+	})
+	require.NoError(t, txErr)
+
+	txErr = db.View(func(txn *badger.Txn) error {
+		// only the latest ones are kept
+		results, err := scanBulletins(context.Background(), txn, &BulletinFilter{StartTime: TimestampNow, EndTime: TimestampNow + n})
+		require.NoError(t, err)
+		require.Len(t, results, MaxQueryResultCount)
+		require.EqualValues(t, TimestampNow+n-1, results[0].Timestamp)
+		require.EqualValues(t, TimestampNow+n-MaxQueryResultCount, results[MaxQueryResultCount-1].Timestamp)
+
+		// the bulletins out of the window are skipped in every topic
+		results, err = scanBulletins(context.Background(), txn, &BulletinFilter{StartTime: TimestampNow + 100, EndTime: TimestampNow + 109})
+		require.NoError(t, err)
+		require.Len(t, results, 10)
+		require.EqualValues(t, TimestampNow+109, results[0].Timestamp)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = scanBulletins(ctx, txn, &BulletinFilter{StartTime: TimestampNow, EndTime: TimestampNow + n})
+		require.ErrorIs(t, err, context.Canceled)
+		return nil
+	})
+	require.NoError(t, txErr)
+}
+
+func TestNextTopicPrefix(t *testing.T) {
+	next, ok := nextTopicPrefix([]byte{2, 0, 0, 0, 0, 0, 0, 0x01, 0xff})
+	require.True(t, ok)
+	require.Equal(t, []byte{2, 0, 0, 0, 0, 0, 0, 0x02, 0x00}, next)
+	_, ok = nextTopicPrefix([]byte{2, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	require.False(t, ok)
+}
+
 // -----------------------------Bad Cases--------------------------------
 
 func TestGetBulletinWithWrongGanyUrl(t *testing.T) {
@@ -20,7 +20,7 @@ func makeFakeEmptyBytes(length int) []byte {
 }
 
 func CreateTestApp(db *badger.DB) *GanyApplication {
-	return NewGanyApplication(db, "10000", tmlog.MustNewDefaultLogger(tmlog.LogFormatPlain, tmlog.LogLevelInfo, false))
+	return NewGanyApplication(db, "10000", DefaultConfig(), nil, tmlog.MustNewDefaultLogger(tmlog.LogFormatPlain, tmlog.LogLevelInfo, false))
 }
 
 // ----------------------------Normal Cases------------------------------------
@@ -33,7 +33,7 @@ func TestCheckTx(t *testing.T) {
 
 	sp1 := &pb.StochasticPayment{
 		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
-		DueTime:                 TimestampDuration,
+		DueTime:                 TimestampDueTime,
 		Probability:             1000,
 		Nonces:                  makeFakeEmptyBytes(32),
 		Payee:                   TestAddress.Bytes(),
@@ -65,7 +65,7 @@ func TestCheckTx(t *testing.T) {
 
 	sp2 := &pb.StochasticPayment{
 		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
-		DueTime:                 TimestampDuration,
+		DueTime:                 TimestampDueTime,
 		Probability:             1000,
 		Nonces:                  makeFakeEmptyBytes(32),
 		Payee:                   TestAddress.Bytes(),
@@ -116,7 +116,7 @@ func TestGanyAppBasic(t *testing.T) {
 	// 2. DeliverTx
 	sp1 := &pb.StochasticPayment{
 		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
-		DueTime:                 TimestampDuration,
+		DueTime:                 TimestampDueTime,
 		Probability:             1000,
 		Nonces:                  makeFakeEmptyBytes(32),
 		Payee:                   TestAddress.Bytes(),
@@ -147,7 +147,7 @@ func TestGanyAppBasic(t *testing.T) {
 
 	sp2 := &pb.StochasticPayment{
 		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
-		DueTime:                 TimestampDuration,
+		DueTime:                 TimestampDueTime,
 		Probability:             1000,
 		Nonces:                  makeFakeEmptyBytes(32),
 		Payee:                   TestAddress.Bytes(),
@@ -232,7 +232,7 @@ func TestGanyAppBasic(t *testing.T) {
 	// 2. DeliverTx
 	sp3 := &pb.StochasticPayment{
 		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
-		DueTime:                 TimestampDuration,
+		DueTime:                 TimestampDueTime,
 		Probability:             1000,
 		Nonces:                  makeFakeEmptyBytes(32),
 		Payee:                   TestAddress.Bytes(),
@@ -299,7 +299,7 @@ func TestCheckTxWithInvalidBytes(t *testing.T) {
 
 	sp1 := &pb.StochasticPayment{
 		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
-		DueTime:                 TimestampDuration,
+		DueTime:                 TimestampDueTime,
 		Probability:             1000,
 		Nonces:                  makeFakeEmptyBytes(32),
 		Payee:                   TestAddress.Bytes(),
@@ -339,7 +339,7 @@ func TestCheckTxWithInvalidBulletin(t *testing.T) {
 
 	sp1 := &pb.StochasticPayment{
 		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
-		DueTime:                 TimestampDuration,
+		DueTime:                 TimestampDueTime,
 		Probability:             1000,
 		Nonces:                  makeFakeEmptyBytes(32),
 		Payee:                   TestAddress.Bytes(),
@@ -369,3 +369,51 @@ func TestCheckTxWithInvalidBulletin(t *testing.T) {
 	})
 	require.EqualValues(t, CheckTxCodeErrorInvalidBulletin, resp.Code)
 }
+
+type fakeSbchClock int64
+
+func (c fakeSbchClock) GetLatestBlockTime() int64 {
+	return int64(c)
+}
+
+func TestCheckTxPaymentDue(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions(TestDataDir))
+	require.NoError(t, err)
+	defer cleanData(db)
+
+	sp := &pb.StochasticPayment{
+		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
+		DueTime:                 TimestampDueTime,
+		Probability:             1000,
+		Nonces:                  makeFakeEmptyBytes(32),
+		Payee:                   TestAddress.Bytes(),
+		AmountToPayee:           gethcmn.FromHex("0x50"),
+		AmountToValidator:       gethcmn.FromHex("0x14"),
+		Signature:               makeFakeEmptyBytes(65),
+	}
+	b := &pb.Bulletin{
+		Type:        pb.Bulletin_BLOG,
+		Topic:       []byte{0x12},
+		Timestamp:   TimestampNow,
+		Duration:    TimestampDuration,
+		From:        TestAddress.Bytes(),
+		ContentType: "My Blog",
+		ContentList: [][]byte{
+			{1, 2},
+		},
+	}
+	checkTx := func(blockTime int64) uint32 {
+		ganyApp := NewGanyApplication(db, "10000", DefaultConfig(), fakeSbchClock(blockTime), tmlog.NewNopLogger())
+		return ganyApp.CheckTx(abcitypes.RequestCheckTx{
+			Tx:   pb.CreateGanyTx(sp, b, nil, nil),
+			Type: abcitypes.CheckTxType_New,
+		}).Code
+	}
+
+	require.EqualValues(t, CheckTxCodeOK, checkTx(TimestampDueTime-120))
+	// the local clock is used before any smartBCH block is followed
+	require.EqualValues(t, CheckTxCodeOK, checkTx(0))
+	// due within the window
+	require.EqualValues(t, CheckTxCodeErrorPaymentDue, checkTx(TimestampDueTime-30))
+	require.EqualValues(t, CheckTxCodeErrorPaymentDue, checkTx(TimestampDueTime+1))
+}
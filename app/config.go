@@ -0,0 +1,22 @@
+package app
+
+import "time"
+
+const DefaultMinDueTimeWindow = time.Minute
+
+type Config struct {
+	Pricing PricingPolicy `mapstructure:"pricing"` // of SBCH
+
+	// the whitelist of the SEP20 tokens accepted besides SBCH, keyed by the hex address of the token
+	Tokens map[string]TokenConfig `mapstructure:"tokens"`
+
+	// the payment must be due later than this after the latest smartBCH block, so it can still be settled
+	MinDueTimeWindow time.Duration `mapstructure:"min-due-time-window"`
+}
+
+// DefaultConfig returns a config which accepts bulletins of any price, paid in SBCH only
+func DefaultConfig() *Config {
+	return &Config{
+		MinDueTimeWindow: DefaultMinDueTimeWindow,
+	}
+}
@@ -10,4 +10,10 @@ var (
 	ErrInvalidOldSN          = errors.New("invalid old SN")
 	ErrCantFindOldBulletin   = errors.New("can't find old bulletin")
 	ErrCantOverwriteBulletin = errors.New("can't overwrite old bulletin")
+
+	// Payment
+	ErrInvalidAmount    = errors.New("invalid amount to validator")
+	ErrUnderpriced      = errors.New("expected value of the payment is below the minimum price")
+	ErrPaymentDue       = errors.New("payment is due or will be due before it can be settled")
+	ErrTokenNotAccepted = errors.New("token of the payment is not accepted")
 )
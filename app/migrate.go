@@ -0,0 +1,169 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+// The bulletins moved from other shards can not be found with the main key map of this shard,
+// because their SNs were generated by the blocks of the original shard.
+// So the migrated key is used to resolve their old gany urls.
+
+func getMigratedKey(topicHash4, sn []byte) []byte {
+	key := make([]byte, MigratedKeyLen)
+	key[0] = MigratedKeyHeadByte
+	copy(key[1:5], topicHash4)
+	copy(key[5:], sn)
+	return key
+}
+
+// MigrateShards moves every bulletin to the shard which owns its topic in `to`, then records `to`
+// in all the databases. `dbs` must contain all the old and new shards, and the nodes must be stopped.
+//
+// It can be re-run with the same `to` after it is interrupted. A bulletin is deleted from its source shard
+// only after it is written into its new shard, so the interrupted migration leaves at most duplicated
+// bulletins, which are overwritten with the same entries and deleted from the source by the re-run.
+func MigrateShards(dbs []*badger.DB, to *ShardMap, logger tmlog.Logger) (int, error) {
+	if !to.IsValid() || int(to.NumOfShards) > len(dbs) {
+		return 0, ErrInvalidShardMap
+	}
+
+	total := 0
+	for i := range dbs {
+		moved, err := migrateShard(dbs, uint32(i), to)
+		if err != nil {
+			return total, fmt.Errorf("migrate shard %d failed: %w", i, err)
+		}
+		logger.Info("shard migrated", "shard", i, "moved", moved, "version", to.Version)
+		total += moved
+	}
+
+	for _, db := range dbs {
+		err := db.Update(func(txn *badger.Txn) error {
+			return putShardMap(txn, to)
+		})
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func migrateShard(dbs []*badger.DB, src uint32, to *ShardMap) (int, error) {
+	batches := make([]*badger.WriteBatch, len(dbs))
+	for i, db := range dbs {
+		batches[i] = db.NewWriteBatch()
+	}
+	defer func() {
+		for _, wb := range batches {
+			wb.Cancel()
+		}
+	}()
+
+	moved := 0
+	err := dbs[src].View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			item := iter.Item()
+			key := item.KeyCopy(nil)
+
+			switch {
+			case len(key) == MainKeyLen && key[0] <= byte(pb.Bulletin_CENSOR):
+				value, err := item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				owner := to.ShardOf(value[:TopicHashEnd])
+				if owner == src {
+					continue
+				}
+
+				sn := key[MainKeyHeadLen : MainKeyHeadLen+8]
+				migratedKey := getMigratedKey(value[:4], sn)
+				if err = setEntryWithExpiry(batches[owner], key, value, item.ExpiresAt()); err != nil {
+					return err
+				}
+				if err = setEntryWithExpiry(batches[owner], migratedKey, key[:MainKeyHeadLen], item.ExpiresAt()); err != nil {
+					return err
+				}
+				if err = batches[src].Delete(key); err != nil {
+					return err
+				}
+				moved++
+
+			case len(key) == MigratedKeyLen && key[0] == MigratedKeyHeadByte:
+				// the bulletins which have been moved into this shard before
+				owner := to.ShardOf(key[1:5])
+				if owner == src {
+					continue
+				}
+				value, err := item.ValueCopy(nil)
+				if err != nil {
+					return err
+				}
+				if err = setEntryWithExpiry(batches[owner], key, value, item.ExpiresAt()); err != nil {
+					return err
+				}
+				if err = batches[src].Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// the copies are committed before the deletes in the source shard, so no bulletin is lost on a crash
+	for i, wb := range batches {
+		if uint32(i) == src {
+			continue
+		}
+		if err = wb.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	if err = batches[src].Flush(); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+func setEntryWithExpiry(wb *badger.WriteBatch, key, value []byte, expiresAt uint64) error {
+	entry := badger.NewEntry(key, value)
+	if expiresAt != 0 {
+		ttl := time.Duration(int64(expiresAt)-time.Now().Unix()) * time.Second
+		if ttl <= 0 {
+			return nil // expired
+		}
+		entry = entry.WithTTL(ttl)
+	}
+	return wb.SetEntry(entry)
+}
+
+// getMigratedMainKeyHead returns ErrKeyNotFound if the bulletin is not moved from other shards
+func getMigratedMainKeyHead(txn *badger.Txn, ganyUrlBz []byte) ([]byte, error) {
+	item, err := txn.Get(getMigratedKey(ganyUrlBz[:4], ganyUrlBz[4:12]))
+	if err == badger.ErrKeyNotFound {
+		return nil, ErrKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	mainKeyHead, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(mainKeyHead) != MainKeyHeadLen {
+		return nil, ErrMainKeyHeadNotFound
+	}
+	return mainKeyHead, nil
+}
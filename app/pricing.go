@@ -0,0 +1,118 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/holiman/uint256"
+
+	pb "github.com/smartbch/ganychain/proto"
+	"github.com/smartbch/ganychain/utils/ugo"
+)
+
+// PricingPolicy decides the minimum expected value of the payment to the validator for a bulletin:
+// BaseFee(type) + FeePerByte * ContentBytes + FeePerHour * TTLHours, all in wei.
+// The expected value of a stochastic payment is AmountToValidator * (Probability + 1) / 2^32,
+// or AmountToPayee * (Probability + 1) / 2^32 for a single receiver payment.
+type PricingPolicy struct {
+	BaseFees       map[string]uint64 `mapstructure:"base-fees"` // keyed by the lower case bulletin type, e.g. "blog"
+	DefaultBaseFee uint64            `mapstructure:"default-base-fee"`
+	FeePerByte     uint64            `mapstructure:"fee-per-byte"` // of the content type and content list
+	FeePerHour     uint64            `mapstructure:"fee-per-hour"` // of the requested TTL, rounded up
+}
+
+// PriceQuote is the breakdown of the minimum expected value of a bulletin
+type PriceQuote struct {
+	BaseFee      *uint256.Int
+	ContentFee   *uint256.Int
+	TTLFee       *uint256.Int
+	MinPrice     *uint256.Int // the minimum expected value
+	ContentBytes uint64
+	TTLHours     uint64
+}
+
+func (p *PricingPolicy) IsFree() bool {
+	if p.DefaultBaseFee != 0 || p.FeePerByte != 0 || p.FeePerHour != 0 {
+		return false
+	}
+	for _, fee := range p.BaseFees {
+		if fee != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *PricingPolicy) Quote(b *pb.Bulletin) *PriceQuote {
+	q := &PriceQuote{
+		ContentBytes: contentBytesOf(b),
+		TTLHours:     ttlHoursOf(b),
+	}
+
+	baseFee, ok := p.BaseFees[strings.ToLower(b.GetType().String())]
+	if !ok {
+		baseFee = p.DefaultBaseFee
+	}
+	q.BaseFee = uint256.NewInt(baseFee)
+	q.ContentFee = uint256.NewInt(0).Mul(uint256.NewInt(p.FeePerByte), uint256.NewInt(q.ContentBytes))
+	q.TTLFee = uint256.NewInt(0).Mul(uint256.NewInt(p.FeePerHour), uint256.NewInt(q.TTLHours))
+	q.MinPrice = uint256.NewInt(0).Add(q.BaseFee, q.ContentFee)
+	q.MinPrice.Add(q.MinPrice, q.TTLFee)
+	return q
+}
+
+// CheckPayment returns ErrUnderpriced if the expected value of the payment is less than the quote of the bulletin
+func (p *PricingPolicy) CheckPayment(sp *pb.StochasticPayment, b *pb.Bulletin) error {
+	if p.IsFree() {
+		return nil
+	}
+	ev, err := ExpectedValueOf(sp)
+	if err != nil {
+		return err
+	}
+	if ev.Lt(p.Quote(b).MinPrice) {
+		return ErrUnderpriced
+	}
+	return nil
+}
+
+// ExpectedValueOf returns the expected value of the payment to the validator.
+// The receiver of a single receiver payment must be the validator, which is checked by the backend.
+func ExpectedValueOf(sp *pb.StochasticPayment) (*uint256.Int, error) {
+	amountBz := sp.GetAmountToValidator()
+	if sp.IsSingleReceiver() {
+		amountBz = sp.GetAmountToPayee()
+	}
+	amount, overflow := ugo.BytesToUint256(amountBz)
+	if overflow {
+		return nil, ErrInvalidAmount
+	}
+	ev, overflow := uint256.NewInt(0).MulOverflow(amount, uint256.NewInt(uint64(sp.GetProbability())+1))
+	if overflow {
+		return nil, ErrInvalidAmount
+	}
+	return ev.Rsh(ev, 32), nil
+}
+
+func contentBytesOf(b *pb.Bulletin) uint64 {
+	n := uint64(len(b.GetContentType()))
+	for _, content := range b.GetContentList() {
+		n += uint64(len(content))
+	}
+	return n
+}
+
+// ttlHoursOf returns the TTL requested by the bulletin, which is clamped to [MinTTL, MaxTTL] like it is stored.
+// It is relative to the bulletin's timestamp, so all the nodes get the same result.
+func ttlHoursOf(b *pb.Bulletin) uint64 {
+	seconds := int64(MinTTL / time.Second)
+	if b.GetDuration() > b.GetTimestamp() {
+		seconds = b.GetDuration() - b.GetTimestamp()
+	}
+	if seconds < int64(MinTTL/time.Second) {
+		seconds = int64(MinTTL / time.Second)
+	} else if seconds > int64(MaxTTL/time.Second) {
+		seconds = int64(MaxTTL / time.Second)
+	}
+	return uint64((seconds + 3599) / 3600)
+}
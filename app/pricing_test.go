@@ -0,0 +1,161 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/badger/v3"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/smartbch/ganychain/contract"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+func TestPricingPolicy(t *testing.T) {
+	policy := &PricingPolicy{
+		BaseFees:       map[string]uint64{"comment": 100},
+		DefaultBaseFee: 1000,
+		FeePerByte:     10,
+		FeePerHour:     1,
+	}
+
+	b := &pb.Bulletin{
+		Type:        pb.Bulletin_BLOG,
+		Timestamp:   TimestampNow,
+		Duration:    TimestampNow + 3*3600 + 1,
+		ContentType: "My Blog",
+		ContentList: [][]byte{
+			{1, 2},
+		},
+	}
+	q := policy.Quote(b)
+	require.EqualValues(t, 9, q.ContentBytes)
+	require.EqualValues(t, 4, q.TTLHours)
+	require.EqualValues(t, 1000+90+4, q.MinPrice.Uint64())
+
+	// the TTL is clamped like it is stored
+	b.Type = pb.Bulletin_COMMENT
+	b.Duration = 0
+	q = policy.Quote(b)
+	require.EqualValues(t, 1, q.TTLHours)
+	require.EqualValues(t, 100+90+1, q.MinPrice.Uint64())
+
+	// 191 * 2^32 / 2^31 = 382
+	sp := &pb.StochasticPayment{
+		Probability:       1<<31 - 1,
+		AmountToValidator: gethcmn.FromHex("0x017d"), // 381
+	}
+	require.Equal(t, ErrUnderpriced, policy.CheckPayment(sp, b))
+	sp.AmountToValidator = gethcmn.FromHex("0x017e") // 382
+	require.NoError(t, policy.CheckPayment(sp, b))
+
+	require.True(t, (&PricingPolicy{}).IsFree())
+	require.NoError(t, (&PricingPolicy{}).CheckPayment(&pb.StochasticPayment{}, b))
+}
+
+func TestCheckTxUnderpriced(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions(TestDataDir))
+	require.NoError(t, err)
+	defer cleanData(db)
+
+	config := DefaultConfig()
+	config.Pricing.DefaultBaseFee = 1000
+	ganyApp := NewGanyApplication(db, "10000", config, nil, tmlog.NewNopLogger())
+
+	sp := &pb.StochasticPayment{
+		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
+		DueTime:                 TimestampDueTime,
+		Probability:             0xFFFFFFFF,
+		Nonces:                  makeFakeEmptyBytes(32),
+		Payee:                   TestAddress.Bytes(),
+		AmountToPayee:           gethcmn.FromHex("0x50"),
+		AmountToValidator:       gethcmn.FromHex("0x14"),
+		Signature:               makeFakeEmptyBytes(65),
+	}
+	b := &pb.Bulletin{
+		Type:        pb.Bulletin_BLOG,
+		Topic:       []byte{0x12},
+		Timestamp:   TimestampNow,
+		Duration:    TimestampDuration,
+		From:        TestAddress.Bytes(),
+		ContentType: "My Blog",
+		ContentList: [][]byte{
+			{1, 2},
+		},
+	}
+	resp := ganyApp.CheckTx(abcitypes.RequestCheckTx{
+		Tx:   pb.CreateGanyTx(sp, b, nil, nil),
+		Type: abcitypes.CheckTxType_New,
+	})
+	require.EqualValues(t, CheckTxCodeErrorUnderpriced, resp.Code)
+
+	sp.AmountToValidator = gethcmn.FromHex("0x03e8") // 1000
+	resp = ganyApp.CheckTx(abcitypes.RequestCheckTx{
+		Tx:   pb.CreateGanyTx(sp, b, nil, nil),
+		Type: abcitypes.CheckTxType_New,
+	})
+	require.EqualValues(t, CheckTxCodeOK, resp.Code)
+}
+
+func TestCheckTxTokenWhitelist(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions(TestDataDir))
+	require.NoError(t, err)
+	defer cleanData(db)
+
+	flexUSD := gethcmn.HexToAddress("0x7b2B3C5308ab5b2a1d9a94d20D35CCDf61e05b72")
+	config := DefaultConfig()
+	config.Pricing.DefaultBaseFee = 1000
+	config.Tokens = map[string]TokenConfig{
+		strings.ToLower(flexUSD.Hex()): {Symbol: "FLEXUSD", Decimals: 18, Pricing: PricingPolicy{DefaultBaseFee: 10}},
+	}
+	ganyApp := NewGanyApplication(db, "10000", config, nil, tmlog.NewNopLogger())
+
+	tokens := ganyApp.GetAcceptedTokens()
+	require.Len(t, tokens, 2)
+	require.Equal(t, contract.SBCHTokenAddress, tokens[0].Address)
+	require.Equal(t, flexUSD, tokens[1].Address)
+	require.EqualValues(t, 10, tokens[1].Pricing.DefaultBaseFee)
+
+	sp := &pb.StochasticPayment{
+		ValidatorPubkeyHashRoot: makeFakeEmptyBytes(32),
+		DueTime:                 TimestampDueTime,
+		Probability:             0xFFFFFFFF,
+		Nonces:                  makeFakeEmptyBytes(32),
+		Payee:                   TestAddress.Bytes(),
+		AmountToPayee:           gethcmn.FromHex("0x50"),
+		AmountToValidator:       gethcmn.FromHex("0x14"), // 20
+		Signature:               makeFakeEmptyBytes(65),
+		Sep20Contract:           flexUSD.Bytes(),
+	}
+	b := &pb.Bulletin{
+		Type:        pb.Bulletin_BLOG,
+		Topic:       []byte{0x12},
+		Timestamp:   TimestampNow,
+		Duration:    TimestampDuration,
+		From:        TestAddress.Bytes(),
+		ContentType: "My Blog",
+		ContentList: [][]byte{
+			{1, 2},
+		},
+	}
+	checkTx := func() uint32 {
+		return ganyApp.CheckTx(abcitypes.RequestCheckTx{
+			Tx:   pb.CreateGanyTx(sp, b, nil, nil),
+			Type: abcitypes.CheckTxType_New,
+		}).Code
+	}
+
+	// priced by the policy of the token
+	require.EqualValues(t, CheckTxCodeOK, checkTx())
+
+	// the same amount is underpriced in SBCH
+	sp.Sep20Contract = nil
+	require.EqualValues(t, CheckTxCodeErrorUnderpriced, checkTx())
+
+	// not on the whitelist
+	sp.Sep20Contract = TestAddress.Bytes()
+	require.EqualValues(t, CheckTxCodeErrorTokenNotAccepted, checkTx())
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/dgraph-io/badger/v3"
+)
+
+// ShardMap: 222||Version4 => json(ShardMap)
+
+var (
+	ErrInvalidShardMap = errors.New("invalid shard map")
+)
+
+// HashRange owns the topic hash prefixes in [Start, the Start of next range)
+type HashRange struct {
+	Start uint32 `json:"start"`
+	Shard uint32 `json:"shard"`
+}
+
+// ShardMap maps the first 4 bytes of a topic hash to a shard.
+// The version 0 map has no ranges, and it uses the legacy `prefix % NumOfShards` mapping.
+type ShardMap struct {
+	Version     uint32      `json:"version"`
+	NumOfShards uint32      `json:"num_of_shards"`
+	Ranges      []HashRange `json:"ranges,omitempty"` // sorted by Start, and the first Start must be 0
+}
+
+func NewLegacyShardMap(numOfShards uint32) *ShardMap {
+	return &ShardMap{
+		Version:     0,
+		NumOfShards: numOfShards,
+	}
+}
+
+func (m *ShardMap) IsLegacy() bool {
+	return len(m.Ranges) == 0
+}
+
+func (m *ShardMap) IsValid() bool {
+	if m.NumOfShards == 0 {
+		return false
+	}
+	if m.IsLegacy() {
+		return true
+	}
+	if m.Ranges[0].Start != 0 {
+		return false
+	}
+	for i, r := range m.Ranges {
+		if r.Shard >= m.NumOfShards {
+			return false
+		}
+		if i > 0 && r.Start <= m.Ranges[i-1].Start {
+			return false
+		}
+	}
+	return true
+}
+
+// ShardOf returns the shard which owns the topic hash prefix (the first 4 bytes of topic hash or gany url)
+func (m *ShardMap) ShardOf(prefix []byte) uint32 {
+	p := binary.BigEndian.Uint32(prefix[:4])
+	if m.IsLegacy() {
+		return p % m.NumOfShards
+	}
+	i := sort.Search(len(m.Ranges), func(i int) bool {
+		return m.Ranges[i].Start > p
+	})
+	return m.Ranges[i-1].Shard
+}
+
+// Resize returns the next version of shard map with `numOfShards` shards.
+// A legacy map is converted into uniform hash ranges. Otherwise, growing splits the widest ranges,
+// and shrinking hands the ranges of removed shards to their preceding neighbours,
+// so only the topics in the affected ranges need to be moved.
+func (m *ShardMap) Resize(numOfShards uint32) *ShardMap {
+	next := &ShardMap{
+		Version:     m.Version + 1,
+		NumOfShards: numOfShards,
+	}
+
+	if m.IsLegacy() {
+		next.Ranges = uniformRanges(numOfShards)
+		return next
+	}
+
+	ranges := append([]HashRange{}, m.Ranges...)
+	for shard := m.NumOfShards; shard < numOfShards; shard++ {
+		ranges = splitWidestRange(ranges, shard)
+	}
+	for i := range ranges {
+		if ranges[i].Shard < numOfShards {
+			continue
+		}
+		if i > 0 {
+			ranges[i].Shard = ranges[i-1].Shard
+		} else {
+			ranges[i].Shard = nextValidShard(ranges, numOfShards)
+		}
+	}
+	next.Ranges = mergeRanges(ranges)
+	return next
+}
+
+func uniformRanges(numOfShards uint32) []HashRange {
+	ranges := make([]HashRange, 0, numOfShards)
+	width := (uint64(math.MaxUint32) + 1) / uint64(numOfShards)
+	for i := uint32(0); i < numOfShards; i++ {
+		ranges = append(ranges, HashRange{Start: uint32(uint64(i) * width), Shard: i})
+	}
+	return ranges
+}
+
+func rangeEnd(ranges []HashRange, i int) uint64 {
+	if i+1 < len(ranges) {
+		return uint64(ranges[i+1].Start)
+	}
+	return uint64(math.MaxUint32) + 1
+}
+
+func splitWidestRange(ranges []HashRange, newShard uint32) []HashRange {
+	widest := 0
+	for i := range ranges {
+		if rangeEnd(ranges, i)-uint64(ranges[i].Start) > rangeEnd(ranges, widest)-uint64(ranges[widest].Start) {
+			widest = i
+		}
+	}
+	mid := uint32((uint64(ranges[widest].Start) + rangeEnd(ranges, widest)) / 2)
+	if mid == ranges[widest].Start {
+		return ranges // cannot be split anymore
+	}
+
+	result := make([]HashRange, 0, len(ranges)+1)
+	result = append(result, ranges[:widest+1]...)
+	result = append(result, HashRange{Start: mid, Shard: newShard})
+	result = append(result, ranges[widest+1:]...)
+	return result
+}
+
+func nextValidShard(ranges []HashRange, numOfShards uint32) uint32 {
+	for _, r := range ranges {
+		if r.Shard < numOfShards {
+			return r.Shard
+		}
+	}
+	return 0
+}
+
+func mergeRanges(ranges []HashRange) []HashRange {
+	result := make([]HashRange, 0, len(ranges))
+	for _, r := range ranges {
+		if len(result) > 0 && result[len(result)-1].Shard == r.Shard {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result
+}
+
+// ----------------------------------------------------------------
+
+func shardMapKey(version uint32) []byte {
+	key := make([]byte, 5)
+	key[0] = ShardMapKeyByte
+	binary.BigEndian.PutUint32(key[1:], version)
+	return key
+}
+
+func putShardMap(txn *badger.Txn, m *ShardMap) error {
+	if !m.IsValid() {
+		return ErrInvalidShardMap
+	}
+	bz, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return txn.Set(shardMapKey(m.Version), bz)
+}
+
+// getLatestShardMap returns nil if no shard map is stored
+func getLatestShardMap(txn *badger.Txn) (*ShardMap, error) {
+	opts := badger.DefaultIteratorOptions
+	opts.Reverse = true
+	opts.Prefix = []byte{ShardMapKeyByte}
+	iter := txn.NewIterator(opts)
+	defer iter.Close()
+
+	iter.Seek(shardMapKey(math.MaxUint32))
+	if !iter.Valid() {
+		return nil, nil
+	}
+
+	var m ShardMap
+	err := iter.Item().Value(func(value []byte) error {
+		return json.Unmarshal(value, &m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
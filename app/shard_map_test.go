@@ -0,0 +1,132 @@
+package app
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+func prefixOf(p uint32) []byte {
+	var bz [4]byte
+	binary.BigEndian.PutUint32(bz[:], p)
+	return bz[:]
+}
+
+func TestShardMapResize(t *testing.T) {
+	legacy := NewLegacyShardMap(2)
+	require.True(t, legacy.IsValid())
+	require.EqualValues(t, 1, legacy.ShardOf(prefixOf(7)))
+	require.EqualValues(t, 0, legacy.ShardOf(prefixOf(8)))
+
+	v1 := legacy.Resize(2)
+	require.True(t, v1.IsValid())
+	require.EqualValues(t, 1, v1.Version)
+	require.EqualValues(t, 0, v1.ShardOf(prefixOf(0)))
+	require.EqualValues(t, 0, v1.ShardOf(prefixOf(math.MaxUint32/2)))
+	require.EqualValues(t, 1, v1.ShardOf(prefixOf(math.MaxUint32/2+1)))
+	require.EqualValues(t, 1, v1.ShardOf(prefixOf(math.MaxUint32)))
+
+	// growing only splits one range, the topics of shard 1 stay where they are
+	v2 := v1.Resize(3)
+	require.True(t, v2.IsValid())
+	require.EqualValues(t, 3, v2.NumOfShards)
+	require.EqualValues(t, 0, v2.ShardOf(prefixOf(0)))
+	require.EqualValues(t, 2, v2.ShardOf(prefixOf(math.MaxUint32/4+1)))
+	require.EqualValues(t, 1, v2.ShardOf(prefixOf(math.MaxUint32)))
+
+	// shrinking hands the ranges of shard 2 to its preceding neighbour
+	v3 := v2.Resize(2)
+	require.True(t, v3.IsValid())
+	require.EqualValues(t, v1.Ranges, v3.Ranges)
+}
+
+func TestMigrateShards(t *testing.T) {
+	dbs := make([]*badger.DB, 2)
+	for i := range dbs {
+		db, err := badger.Open(badger.DefaultOptions(fmt.Sprintf(TestDataDir+"/shard%v", i)))
+		require.NoError(t, err)
+		dbs[i] = db
+	}
+	defer func() {
+		dbs[0].DropAll()
+		dbs[0].Close()
+		cleanData(dbs[1])
+	}()
+
+	b1 := &pb.Bulletin{
+		Type:        pb.Bulletin_BLOG,
+		Topic:       []byte{0x12},
+		Timestamp:   TimestampNow,
+		Duration:    TimestampDuration,
+		From:        TestAddress.Bytes(),
+		ContentType: "My Blog",
+		ContentList: [][]byte{
+			{1, 2},
+		},
+	}
+	topicHash := b1.GetTopicHash()
+
+	// put the bulletin into the shard which does not own its topic in the new map
+	next := NewLegacyShardMap(2).Resize(2)
+	src := 1 - next.ShardOf(topicHash[:])
+	dst := next.ShardOf(topicHash[:])
+
+	tx1 := pb.CreateGanyTx(nil, b1, nil, nil)
+	txErr := dbs[src].Update(func(txn *badger.Txn) error {
+		return putGanyTx(txn, tx1, TimestampBlockOne, 0)
+	})
+	require.NoError(t, txErr)
+
+	sn := genSerialBytes(TimestampBlockOne, 0)
+	ganyUrlBz := make([]byte, 4+8)
+	copy(ganyUrlBz[:4], topicHash[:4])
+	copy(ganyUrlBz[4:], sn[:])
+
+	moved, err := MigrateShards(dbs, next, tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.Equal(t, 1, moved)
+
+	// the old gany url is resolved by the new owner
+	b1Bz, _ := proto.Marshal(b1)
+	txErr = dbs[dst].View(func(txn *badger.Txn) error {
+		txFromDB, err := getGanyTx(txn, ganyUrlBz)
+		require.NoError(t, err)
+		bFromDBBz, _ := txFromDB.GetBulletinBytes()
+		require.EqualValues(t, b1Bz, bFromDBBz)
+
+		results, _, err := queryBulletins(txn, byte(pb.Bulletin_BLOG), topicHash, TimestampNow, TimestampDuration, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return nil
+	})
+	require.NoError(t, txErr)
+
+	// the shard map is recorded in every shard
+	for _, db := range dbs {
+		txErr = db.View(func(txn *badger.Txn) error {
+			m, err := getLatestShardMap(txn)
+			require.NoError(t, err)
+			require.EqualValues(t, next, m)
+			return nil
+		})
+		require.NoError(t, txErr)
+	}
+
+	// re-running an interrupted migration is harmless
+	moved, err = MigrateShards(dbs, next, tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.Equal(t, 0, moved)
+	txErr = dbs[dst].View(func(txn *badger.Txn) error {
+		_, err := getGanyTx(txn, ganyUrlBz)
+		return err
+	})
+	require.NoError(t, txErr)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"sort"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+
+	"github.com/smartbch/ganychain/contract"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+const (
+	SBCHSymbol   = "SBCH"
+	SBCHDecimals = 18
+)
+
+// TokenConfig is a SEP20 token accepted for the payments besides SBCH
+type TokenConfig struct {
+	Symbol   string        `mapstructure:"symbol"`
+	Decimals uint8         `mapstructure:"decimals"`
+	Pricing  PricingPolicy `mapstructure:"pricing"` // in the smallest unit of the token
+}
+
+type AcceptedToken struct {
+	Address  gethcmn.Address
+	Symbol   string
+	Decimals uint8
+	Pricing  *PricingPolicy
+}
+
+// TokenOf returns the token which the payment is signed for, SBCH if it is not set
+func TokenOf(sp *pb.StochasticPayment) gethcmn.Address {
+	if len(sp.GetSep20Contract()) == 0 {
+		return contract.SBCHTokenAddress
+	}
+	return gethcmn.BytesToAddress(sp.GetSep20Contract())
+}
+
+// AcceptedTokenOf returns ErrTokenNotAccepted if the token is not on the whitelist
+func (c *Config) AcceptedTokenOf(token gethcmn.Address) (*AcceptedToken, error) {
+	if token == contract.SBCHTokenAddress {
+		return &AcceptedToken{Address: token, Symbol: SBCHSymbol, Decimals: SBCHDecimals, Pricing: &c.Pricing}, nil
+	}
+	for hexAddr, tokenConfig := range c.Tokens {
+		if gethcmn.IsHexAddress(hexAddr) && gethcmn.HexToAddress(hexAddr) == token {
+			tokenConfig := tokenConfig
+			return &AcceptedToken{
+				Address:  token,
+				Symbol:   tokenConfig.Symbol,
+				Decimals: tokenConfig.Decimals,
+				Pricing:  &tokenConfig.Pricing,
+			}, nil
+		}
+	}
+	return nil, ErrTokenNotAccepted
+}
+
+// AcceptedTokens returns SBCH and the whitelisted tokens sorted by address
+func (c *Config) AcceptedTokens() []*AcceptedToken {
+	sbch, _ := c.AcceptedTokenOf(contract.SBCHTokenAddress)
+	tokens := make([]*AcceptedToken, 0, len(c.Tokens))
+	for hexAddr := range c.Tokens {
+		if !gethcmn.IsHexAddress(hexAddr) {
+			continue
+		}
+		token, _ := c.AcceptedTokenOf(gethcmn.HexToAddress(hexAddr))
+		tokens = append(tokens, token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Address.Hash().Big().Cmp(tokens[j].Address.Hash().Big()) < 0
+	})
+	return append([]*AcceptedToken{sbch}, tokens...)
+}
@@ -0,0 +1,68 @@
+package backend_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartbch/ganychain/backend"
+	"github.com/smartbch/ganychain/follower"
+	pb "github.com/smartbch/ganychain/proto"
+	"github.com/smartbch/ganychain/utils/testutils"
+)
+
+func TestDynamicSetProof(t *testing.T) {
+	targetContract := gethcmn.HexToAddress("0x2222222222222222222222222222222222222222")
+	selector := []byte{0x12, 0x34, 0x56, 0x78}
+	outData := gethcmn.LeftPadBytes([]byte{1}, 32)
+	now := time.Now().Unix()
+
+	genAuthProof := func(chainId int64, outData []byte, signature []byte) *pb.AuthProof {
+		return &pb.AuthProof{
+			DynamicSetProofList: []*pb.DynamicSetProof{
+				{
+					ChainId:                big.NewInt(chainId).Bytes(),
+					TargetContract:         targetContract.Bytes(),
+					FunctionSelector:       0x12345678,
+					OutData:                outData,
+					Authenticator:          validator.Bytes(),
+					MaxTimeDifference:      60,
+					Timestamp:              now,
+					AuthenticatorSignature: signature,
+				},
+			},
+			OrOfAndOfConditions: []*pb.AndOfConditions{
+				{ConditionNumbers: []int32{1}},
+				{ConditionNumbers: nil},
+				{ConditionNumbers: nil},
+			},
+		}
+	}
+	sp := &pb.StochasticPayment{}
+	b := &pb.Bulletin{From: main.Bytes()}
+
+	mockFollower := testutils.NewMockFollower(nil, nil)
+	mockFollower.SetCallResult(targetContract, selector, outData)
+	callers := backend.NewChainRegistry(mockFollower)
+
+	// smartBCH proofs are checked by calling the contract on the followed state
+	mockFollower.SetBlockTime(now + 30)
+	require.True(t, genAuthProof(follower.SBCHChainId, outData, nil).CheckAuthProof(sp, b, callers))
+	require.False(t, genAuthProof(follower.SBCHChainId, []byte{2}, nil).CheckAuthProof(sp, b, callers))
+
+	// the followed state is too far from the timestamp of the proof
+	mockFollower.SetBlockTime(now - 61)
+	require.False(t, genAuthProof(follower.SBCHChainId, outData, nil).CheckAuthProof(sp, b, callers))
+
+	// the other chains need the signature of the authenticator
+	callInfoHash := genAuthProof(1, outData, nil).DynamicSetProofList[0].EthCallInfoHash(main.Bytes())
+	sig, err := gethcrypto.Sign(callInfoHash, validatorPrivateKey)
+	require.NoError(t, err)
+	require.True(t, genAuthProof(1, outData, sig).CheckAuthProof(sp, b, callers))
+	require.True(t, genAuthProof(1, outData, sig).CheckAuthProof(sp, b, nil))
+	require.False(t, genAuthProof(1, []byte{2}, sig).CheckAuthProof(sp, b, callers))
+}
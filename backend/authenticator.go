@@ -0,0 +1,75 @@
+package backend
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"errors"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+var (
+	ErrAuthenticatorDisabled = errors.New("authenticator is disabled")
+	ErrUnknownChain          = errors.New("chain is not registered")
+)
+
+// loadAuthenticatorKey returns nil if no key file is configured, which disables the authenticator
+func loadAuthenticatorKey(keyFile string) *ecdsa.PrivateKey {
+	if keyFile == "" {
+		return nil
+	}
+	key, err := gethcrypto.LoadECDSA(keyFile)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (backend *Backend) GetAuthenticatorAddress() (gethcmn.Address, bool) {
+	if backend.authenticatorKey == nil {
+		return gethcmn.Address{}, false
+	}
+	return gethcrypto.PubkeyToAddress(backend.authenticatorKey.PublicKey), true
+}
+
+// SignDynamicSetProof runs the call of a dynamic set on the chain `chainId` with `from` as the sender,
+// and signs the output with the authenticator key. The chain is smartBCH, which is called on the followed state,
+// or one of the configured chains. The timestamp of the proof is the time of the block the call runs on.
+func (backend *Backend) SignDynamicSetProof(chainId *uint256.Int, from, targetContract gethcmn.Address,
+	functionSelector uint32, maxTimeDifference int64) (*pb.DynamicSetProof, error) {
+
+	authenticator, ok := backend.GetAuthenticatorAddress()
+	if !ok {
+		return nil, ErrAuthenticatorDisabled
+	}
+	caller := backend.chains.GetCaller(chainId)
+	if caller == nil {
+		return nil, ErrUnknownChain
+	}
+
+	var selector [4]byte
+	binary.BigEndian.PutUint32(selector[:], functionSelector)
+	outData, blockTime, err := caller.CallContract(from, targetContract, selector[:])
+	if err != nil {
+		return nil, err
+	}
+
+	dsp := &pb.DynamicSetProof{
+		ChainId:           caller.GetChainId().Bytes(),
+		TargetContract:    targetContract.Bytes(),
+		FunctionSelector:  functionSelector,
+		OutData:           outData,
+		Authenticator:     authenticator.Bytes(),
+		MaxTimeDifference: maxTimeDifference,
+		Timestamp:         blockTime,
+	}
+	dsp.AuthenticatorSignature, err = gethcrypto.Sign(dsp.EthCallInfoHash(from.Bytes()), backend.authenticatorKey)
+	if err != nil {
+		return nil, err
+	}
+	return dsp, nil
+}
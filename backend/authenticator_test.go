@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"testing"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartbch/ganychain/follower"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+type fakeCaller struct {
+	follower.FollowerService
+	outData []byte
+}
+
+func (f *fakeCaller) GetChainId() *uint256.Int {
+	return uint256.NewInt(follower.SBCHChainId)
+}
+
+func (f *fakeCaller) CallContract(from, to gethcmn.Address, data []byte) ([]byte, int64, error) {
+	return f.outData, 1000, nil
+}
+
+func TestSignDynamicSetProof(t *testing.T) {
+	from := gethcmn.HexToAddress("0x06C14ED469FB93545cbF071b593D8f90194Ede62")
+	targetContract := gethcmn.HexToAddress("0x2222222222222222222222222222222222222222")
+	outData := gethcmn.LeftPadBytes([]byte{1}, 32)
+
+	sbch := &fakeCaller{outData: outData}
+	otherChain := NewEVMChain(uint256.NewInt(1), nil, 0)
+	backend := &Backend{follower: sbch, chains: NewChainRegistry(sbch, otherChain)}
+	chainId := uint256.NewInt(follower.SBCHChainId)
+	_, err := backend.SignDynamicSetProof(chainId, from, targetContract, 0x12345678, 60)
+	require.Equal(t, ErrAuthenticatorDisabled, err)
+
+	backend.authenticatorKey, _ = gethcrypto.GenerateKey()
+	_, err = backend.SignDynamicSetProof(uint256.NewInt(2), from, targetContract, 0x12345678, 60)
+	require.Equal(t, ErrUnknownChain, err)
+
+	dsp, err := backend.SignDynamicSetProof(chainId, from, targetContract, 0x12345678, 60)
+	require.NoError(t, err)
+	require.Equal(t, outData, dsp.OutData)
+	require.EqualValues(t, 1000, dsp.Timestamp)
+	require.Equal(t, chainId.Bytes(), dsp.ChainId)
+
+	ap := &pb.AuthProof{
+		DynamicSetProofList: []*pb.DynamicSetProof{dsp},
+		OrOfAndOfConditions: []*pb.AndOfConditions{
+			{ConditionNumbers: []int32{1}},
+			{ConditionNumbers: nil},
+			{ConditionNumbers: nil},
+		},
+	}
+	// checked by the signature on the nodes which do not follow smartBCH
+	require.True(t, ap.CheckAuthProof(&pb.StochasticPayment{}, &pb.Bulletin{From: from.Bytes()}, nil))
+	require.False(t, ap.CheckAuthProof(&pb.StochasticPayment{}, &pb.Bulletin{From: targetContract.Bytes()}, nil))
+}
@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"time"
 
+	geth "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -21,8 +22,8 @@ import (
 	"github.com/holiman/uint256"
 	"github.com/smartbch/merkletree"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmlog "github.com/tendermint/tendermint/libs/log"
 	"github.com/vechain/go-ecvrf"
-	"golang.org/x/crypto/sha3"
 
 	"github.com/smartbch/ganychain/app"
 	"github.com/smartbch/ganychain/contract"
@@ -39,24 +40,80 @@ var (
 	validatorPrivateKey, _ = gethcrypto.HexToECDSA("5f41e5ff714e6e9df08fefd36931b908c04b4fd6d90d70223abd85128f56afb9") // local bch test key
 )
 
+// the time to wait for the receipt of a payment tx
+const settlementTimeout = 20 * time.Second
+
 var _ BackendService = &Backend{}
 
 type Backend struct {
 	numOfShards uint32
+	shardMap    *app.ShardMap
 	apps        []app.GanyApp // gany applications
 	follower    follower.FollowerService
 	sbchClient  web3client.Web3Client
+	chains      *ChainRegistry // the chains which the dynamic set proofs are checked on
+
+	config           *Config
+	limiter          *rateLimiter
+	bulletinCache    *bulletinCache
+	pendingSpends    *pendingSpends
+	callSlots        chan struct{}     // bounds the concurrent calls of EthCall
+	authenticatorKey *ecdsa.PrivateKey // nil if the node is not an authenticator
+	logger           tmlog.Logger
 }
 
-func NewBackend(apps []app.GanyApp, follower follower.FollowerService, sbchClient web3client.Web3Client) BackendService {
+func NewBackend(apps []app.GanyApp, follower follower.FollowerService, sbchClient web3client.Web3Client,
+	config *Config, logger tmlog.Logger) BackendService {
+
+	cache := newBulletinCache(config.BulletinCacheSize, config.BulletinCacheTTL)
+	for _, a := range apps {
+		a.SetOverwriteListener(cache.invalidate)
+	}
+	syncedHeight := func() int64 {
+		return follower.GetSyncStatus().SyncedHeight
+	}
+	maxConcurrentCalls := config.MaxConcurrentCalls
+	if maxConcurrentCalls < 1 {
+		maxConcurrentCalls = 1
+	}
 	return &Backend{
-		numOfShards: uint32(len(apps)),
-		apps:        apps,
-		follower:    follower,
-		sbchClient:  sbchClient,
+		numOfShards:      uint32(len(apps)),
+		shardMap:         loadShardMap(apps),
+		apps:             apps,
+		follower:         follower,
+		sbchClient:       sbchClient,
+		chains:           newChainRegistry(follower, config),
+		config:           config,
+		limiter:          newRateLimiter(config),
+		bulletinCache:    cache,
+		pendingSpends:    newPendingSpends(syncedHeight, follower.GetLatestBlockTime),
+		callSlots:        make(chan struct{}, maxConcurrentCalls),
+		authenticatorKey: loadAuthenticatorKey(config.AuthenticatorKeyFile),
+		logger:           logger,
 	}
 }
 
+// loadShardMap returns the latest shard map recorded in the shards,
+// or the legacy map if the shards have never been migrated.
+func loadShardMap(apps []app.GanyApp) *app.ShardMap {
+	shardMap := app.NewLegacyShardMap(uint32(len(apps)))
+	for _, a := range apps {
+		m, err := a.GetShardMap()
+		if err != nil {
+			panic(err)
+		}
+		if m != nil && m.Version > shardMap.Version {
+			shardMap = m
+		}
+	}
+
+	if shardMap.NumOfShards != uint32(len(apps)) {
+		panic(fmt.Sprintf("number of shards (%d) mismatches shard map version %d (%d shards)",
+			len(apps), shardMap.Version, shardMap.NumOfShards))
+	}
+	return shardMap
+}
+
 func (backend *Backend) GetAllChainIds() []string {
 	chainIds := make([]string, 0, backend.numOfShards)
 	for _, a := range backend.apps {
@@ -66,29 +123,34 @@ func (backend *Backend) GetAllChainIds() []string {
 }
 
 func (backend *Backend) PutBulletin(tx pb.GanyTx) (tmbytes.HexBytes, error) {
-	// 1. check the tx
-	if isValid, err := tx.IsValid(); !isValid {
+	// 1. check the tx and 2. prepare tx data
+	sp, b, ap, err := parseGanyTx(tx)
+	if err != nil {
 		return nil, err
 	}
 
-	// 2. prepare tx data
-	sp, err := tx.GetStochasticPayment()
-	if err != nil {
+	if err = backend.checkSingleReceiver(sp); err != nil {
 		return nil, err
 	}
 
-	b, err := tx.GetBulletin()
-	if err != nil {
+	if err = backend.checkDueTime(sp, b); err != nil {
 		return nil, err
 	}
 
-	ap, err := tx.GetAuthProof()
-	if err != nil {
+	// the validator set which the payment is signed for, only AB mode has a root
+	var tree *merkletree.MerkleTree
+	if !sp.IsSingleReceiver() {
+		if tree, err = backend.validatorTreeOf(sp.ValidatorPubkeyHashRoot); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = backend.checkPayment(sp, b); err != nil {
 		return nil, err
 	}
 
-	msg := sp.GenEIP712MsgForAB(contract.SBCHTokenAddress)
-	typedData := ethutils.GetStochasticPayTypedData(ethutils.EIP712TypesForAB, msg, contract.StochasticPayVRFAddress)
+	msg, eip712Types := genEIP712Msg(sp)
+	typedData := ethutils.GetStochasticPayTypedData(eip712Types, msg, contract.StochasticPayVRFAddress)
 	eip712Hash, err := ethutils.GetTypedDataHash(typedData)
 
 	// 3. check the address
@@ -97,11 +159,22 @@ func (backend *Backend) PutBulletin(tx pb.GanyTx) (tmbytes.HexBytes, error) {
 		return nil, err
 	}
 
-	// 4. check balance and get nonces
-	_, _, err = backend.checkNoncesAndBalance(sp, *address)
+	if err = backend.checkAddressRateLimits(b, *address); err != nil {
+		return nil, err
+	}
+
+	// 4. check balance and get nonces, the amount is reserved until the payment is settled
+	spend, err := backend.checkNoncesAndBalance(sp, *address, true)
 	if err != nil {
 		return nil, err
 	}
+	payTxSent := false
+	defer func() {
+		// a sent payment may still be settled before its due time, so it is held until then
+		if !payTxSent {
+			backend.pendingSpends.release(spend)
+		}
+	}()
 
 	// 5. check auth
 	err = backend.checkAuth(sp, b, ap)
@@ -117,22 +190,22 @@ func (backend *Backend) PutBulletin(tx pb.GanyTx) (tmbytes.HexBytes, error) {
 
 	// 7. send to ganychain
 	topicHash := b.GetTopicHash()
-	shardIndex := binary.BigEndian.Uint32(topicHash[:4]) % backend.numOfShards
+	shardIndex := backend.shardMap.ShardOf(topicHash[:])
 
 	commitResult, err := backend.apps[shardIndex].BroadcastTx(tx)
 	if err != nil {
-		return nil, err
+		return nil, NewShardUnavailableError([]ShardFailure{backend.shardFailure(shardIndex, err.Error())})
 	}
 
 	if commitResult.CheckTx.GetCode() != 0 {
-		return nil, fmt.Errorf("code: %v, error: %v", commitResult.CheckTx.GetCode(), commitResult.CheckTx.GetLog())
+		return nil, NewRejectedTxError(commitResult.CheckTx.GetCode(), commitResult.CheckTx.GetLog())
 	}
 
 	if commitResult.DeliverTx.GetCode() != 0 {
-		return nil, fmt.Errorf("code: %v, error: %v", commitResult.DeliverTx.GetCode(), commitResult.DeliverTx.GetLog())
+		return nil, NewRejectedTxError(commitResult.DeliverTx.GetCode(), commitResult.DeliverTx.GetLog())
 	}
 
-	// 8. call payToAB
+	// 8. call payToAB or payToSingleReciever
 	stochasticPay, err := contract.NewStochasticPayVRF(contract.StochasticPayVRFAddress, backend.sbchClient)
 	if err != nil {
 		return nil, err
@@ -142,41 +215,51 @@ func (backend *Backend) PutBulletin(tx pb.GanyTx) (tmbytes.HexBytes, error) {
 	if err != nil {
 		return nil, err
 	}
-	auth.GasLimit = 8000000
-	auth.GasPrice = big.NewInt(10000000000)
 
 	r, s, v := sp.GetRSV()
-	payABTx, err := backend.callPayToAB(stochasticPay, auth, msg, r, s, v, pi)
+	var payTx *gethtypes.Transaction
+	if sp.IsSingleReceiver() {
+		payTx, err = backend.callPayToSR(stochasticPay, auth, msg, r, s, v, pi)
+	} else {
+		payTx, err = backend.callPayToAB(stochasticPay, auth, msg, r, s, v, pi, tree)
+	}
 	if err != nil {
-		return nil, err
+		return nil, NewSettlementFailedError("", err)
 	}
-
-	fmt.Printf("payABTx: %v\n", payABTx.Hash())
+	payTxSent = true
+	backend.logger.Debug("payment tx sent", "tx", payTx.Hash().Hex())
 
 	// 9. check transaction receipt
-	timeoutCtx, _ := context.WithTimeout(context.Background(), 20*time.Second)
-	err = ugo.Retry(timeoutCtx, "Check TxReceipt", 4e3, func() error {
-		payABTxReceipt, err := backend.sbchClient.TransactionReceipt(context.Background(), payABTx.Hash())
-		if err != nil {
-			return err
-		}
-
-		fmt.Printf("payABTx Receipt: %+v\n", payABTxReceipt)
+	if err = backend.awaitSettlement(spend, payTx.Hash(), settlementTimeout); err != nil {
+		return nil, err
+	}
+	return commitResult.Hash, nil
+}
 
-		if payABTxReceipt.Status != gethtypes.ReceiptStatusSuccessful {
-			return fmt.Errorf("payToAB is not successful: %v", payABTxReceipt.Status)
-		}
-		return nil
+// awaitSettlement waits for the receipt of the payment tx. The reservation of the payment is released if the tx
+// is reverted, since the wallet is not charged. It is kept until the due time if the outcome is unknown.
+func (backend *Backend) awaitSettlement(spend *pendingSpend, txHash gethcmn.Hash, timeout time.Duration) error {
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var receipt *gethtypes.Receipt
+	err := ugo.Retry(timeoutCtx, "Check TxReceipt", 4e3, func() (err error) {
+		receipt, err = backend.sbchClient.TransactionReceipt(context.Background(), txHash)
+		return err
 	})
-
 	if err != nil {
-		return nil, err
+		return NewSettlementFailedError(txHash.Hex(), err)
 	}
-	return commitResult.Hash, nil
+
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		backend.pendingSpends.release(spend)
+		return NewSettlementFailedError(txHash.Hex(), fmt.Errorf("payment is not successful: %v", receipt.Status))
+	}
+	backend.pendingSpends.settle(spend, receipt.BlockNumber.Int64())
+	return nil
 }
 
 func (backend *Backend) callPayToAB(stochasticPay *contract.StochasticPayVRF, auth *bind.TransactOpts,
-	msg eip712types.TypedDataMessage, r, s [32]byte, v byte, pi []byte) (*gethtypes.Transaction, error) {
+	msg eip712types.TypedDataMessage, r, s [32]byte, v byte, pi []byte, tree *merkletree.MerkleTree) (*gethtypes.Transaction, error) {
 
 	payerSalt := msg["payerSalt"].(string)
 	pkHashRoot := msg["pkHashRoot"].(string)
@@ -214,12 +297,22 @@ func (backend *Backend) callPayToAB(stochasticPay *contract.StochasticPayVRF, au
 		S:                            s,
 	}
 
-	tree, err := backend.genValidatorPubKeysMerkleTree()
+	proof, err := cryptoutils.GetProof(tree, validatorPubKeyXY)
 	if err != nil {
 		return nil, err
 	}
 
-	proof, err := cryptoutils.GetProof(tree, validatorPubKeyXY)
+	stochasticPayABI, err := contract.StochasticPayVRFMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := stochasticPayABI.Pack("payToAB", proof, pi, param)
+	if err != nil {
+		return nil, err
+	}
+
+	err = backend.setSettlementFee(context.Background(), auth, contract.StochasticPayVRFAddress, data)
 	if err != nil {
 		return nil, err
 	}
@@ -231,81 +324,187 @@ func (backend *Backend) callPayToAB(stochasticPay *contract.StochasticPayVRF, au
 	return payABTx, nil
 }
 
-func (backend *Backend) genValidatorPubKeysMerkleTree() (*merkletree.MerkleTree, error) {
-	validatorPubKeys := backend.follower.GetValidatorPubKeyList()
+func (backend *Backend) callPayToSR(stochasticPay *contract.StochasticPayVRF, auth *bind.TransactOpts,
+	msg eip712types.TypedDataMessage, r, s [32]byte, v byte, pi []byte) (*gethtypes.Transaction, error) {
 
-	leaves := make([]merkletree.Content, 0, len(validatorPubKeys))
-	for _, cpk := range validatorPubKeys {
-		xy, err := cryptoutils.CompressedPubKeyToXY(cpk)
-		if err != nil {
-			return nil, err
-		}
+	param := genPayToSRParams(msg, r, s, v)
+
+	stochasticPayABI, err := contract.StochasticPayVRFMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := stochasticPayABI.Pack("payToSingleReciever", pi, param)
+	if err != nil {
+		return nil, err
+	}
+
+	err = backend.setSettlementFee(context.Background(), auth, contract.StochasticPayVRFAddress, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return stochasticPay.PayToSingleReciever(auth, pi, param)
+}
+
+// genPayToSRParams packs the EIP712 message of SR mode into the params of payToSingleReciever
+func genPayToSRParams(msg eip712types.TypedDataMessage, r, s [32]byte, v byte) contract.StochasticPayVRFParamsSr {
+	payerSaltPk0 := msg["payerSalt_pk0"].(string)
+	pkTail := msg["pkTail"].(string)
+	payeeAddrDueTime64Prob32 := msg["payeeAddr_dueTime64_prob32"].(string)
+	seenNonces := msg["seenNonces"].(string)
+	sep20ContractAmount := msg["sep20Contract_amount"].(string)
+
+	// v is packed into the lowest byte of payerSalt_pk0
+	var payerSaltPk0VBz [32]byte
+	payerSaltPk0Bz, _ := hexutil.Decode(payerSaltPk0)
+	copy(payerSaltPk0VBz[:31], payerSaltPk0Bz[:31])
+	payerSaltPk0VBz[31] = v
+
+	pkTailBigInt, _ := new(big.Int).SetString(pkTail, 0)
+	payeeAddrDueTime64Prob32BigInt, _ := new(big.Int).SetString(payeeAddrDueTime64Prob32, 0)
+	seenNoncesBigInt, _ := new(big.Int).SetString(seenNonces, 0)
+	sep20ContractAmountBigInt, _ := new(big.Int).SetString(sep20ContractAmount, 0)
+
+	return contract.StochasticPayVRFParamsSr{
+		PayerSaltPk0V:            new(big.Int).SetBytes(payerSaltPk0VBz[:]),
+		PkTail:                   pkTailBigInt,
+		PayeeAddrDueTime64Prob32: payeeAddrDueTime64Prob32BigInt,
+		SeenNonces:               seenNoncesBigInt,
+		Sep20ContractAmount:      sep20ContractAmountBigInt,
+		R:                        r,
+		S:                        s,
+	}
+}
 
-		leaves = append(leaves, cryptoutils.MerkleLeaf{Bz: xy})
+// genEIP712Msg returns the EIP712 message and types of the payment mode
+func genEIP712Msg(sp *pb.StochasticPayment) (eip712types.TypedDataMessage, []eip712types.Type) {
+	if sp.IsSingleReceiver() {
+		return sp.GenEIP712MsgForSR(app.TokenOf(sp)), ethutils.EIP712TypesForSR
 	}
-	return merkletree.NewTreeWithHashStrategy(leaves, sha3.NewLegacyKeccak256)
+	return sp.GenEIP712MsgForAB(app.TokenOf(sp)), ethutils.EIP712TypesForAB
+}
+
+// checkSingleReceiver checks that a single receiver payment is paid to this validator, with its VRF public key
+func (backend *Backend) checkSingleReceiver(sp *pb.StochasticPayment) error {
+	if !sp.IsSingleReceiver() {
+		return nil
+	}
+
+	validatorAddr := gethcrypto.PubkeyToAddress(validatorPrivateKey.PublicKey)
+	if receiver := gethcmn.BytesToAddress(sp.Payee); receiver != validatorAddr {
+		return NewInvalidTxError(fmt.Errorf("receiver %v of single receiver payment is not the validator", receiver.Hex()))
+	}
+
+	if !bytes.Equal(sp.ValidatorPubkey, gethcrypto.CompressPubkey(&validatorPrivateKey.PublicKey)) {
+		return NewInvalidTxError(errors.New("VRF public key of single receiver payment mismatches the validator"))
+	}
+	return nil
 }
 
 func (backend *Backend) checkAndRestoreAddress(sp *pb.StochasticPayment, b *pb.Bulletin, eip712Hash []byte) (*gethcmn.Address, error) {
 	address, _, err := ethutils.EcRecover(eip712Hash, sp.Signature)
 	if err != nil {
+		return nil, NewBadSignatureError(err.Error(), nil)
+	}
+
+	if err = backend.checkDelegatedAddr(b, *address); err != nil {
 		return nil, err
 	}
+	return address, nil
+}
 
+// checkDelegatedAddr checks whether the signer of the stochastic payment is the delegated address of the bulletin's author
+func (backend *Backend) checkDelegatedAddr(b *pb.Bulletin, address gethcmn.Address) error {
 	mainAddr := gethcmn.BytesToAddress(b.From[:])
 	delegatedAddr, err := backend.follower.GetDelegatedAddrByMainAddr(mainAddr)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if delegatedAddr.Hex() != address.Hex() {
-		return nil, errors.New("invalid address or delegated address")
+		return NewBadSignatureError("invalid address or delegated address", &BadSignatureData{
+			Signer:        address.Hex(),
+			DelegatedAddr: delegatedAddr.Hex(),
+		})
 	}
-
-	return address, nil
+	return nil
 }
 
-func (backend *Backend) checkNoncesAndBalance(sp *pb.StochasticPayment, address gethcmn.Address) (*uint256.Int, *uint256.Int, error) {
-	nonces, balance, err := backend.follower.LoadWalletInStochasticPay(contract.SBCHTokenAddress, address)
+// checkNoncesAndBalance checks the balance minus the amount reserved by the payments in flight,
+// and reserves the amount of this payment if `reserve` is set.
+func (backend *Backend) checkNoncesAndBalance(sp *pb.StochasticPayment, address gethcmn.Address, reserve bool) (*pendingSpend, error) {
+	// the wallet would be stale
+	if err := backend.checkFollowerLag(); err != nil {
+		return nil, err
+	}
+
+	nonces, balance, err := backend.follower.LoadWalletInStochasticPay(app.TokenOf(sp), address)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	noncesBz := nonces.Bytes32()
 	if !bytes.Equal(noncesBz[:], sp.Nonces[:]) {
-		return nil, nil, errors.New("invalid stochastic pay nonces")
+		return nil, NewInvalidTxError(errors.New("invalid stochastic pay nonces"))
 	}
 
 	amountToPayee256, overflow := ugo.BytesToUint256(sp.AmountToPayee)
 	if overflow {
-		return nil, nil, errors.New("invalid amountToPayee")
+		return nil, NewInvalidTxError(errors.New("invalid amountToPayee"))
 	}
 
 	amountToValidator256, overflow := ugo.BytesToUint256(sp.AmountToValidator)
 	if overflow {
-		return nil, nil, errors.New("invalid amountToValidator")
+		return nil, NewInvalidTxError(errors.New("invalid amountToValidator"))
 	}
 
-	if balance.Lt(uint256.NewInt(0).Add(amountToPayee256, amountToValidator256)) {
-		return nil, nil, errors.New("insufficient balance")
+	required := uint256.NewInt(0).Add(amountToPayee256, amountToValidator256)
+	key := walletKey{token: app.TokenOf(sp), payer: address}
+	spend, reserved, ok := backend.pendingSpends.reserve(key, balance, required, sp.DueTime, !reserve)
+	if !ok {
+		data := InsufficientBalanceData{
+			Address:  address.Hex(),
+			Balance:  balance.ToBig().String(),
+			Required: required.ToBig().String(),
+		}
+		if !reserved.IsZero() {
+			data.Reserved = reserved.ToBig().String()
+		}
+		return nil, NewInsufficientBalanceError(data)
 	}
-
-	return amountToPayee256, amountToValidator256, nil
+	return spend, nil
 }
 
 func (backend *Backend) checkProb32(privateKey *ecdsa.PrivateKey, msg eip712types.TypedDataMessage, prob32 uint32) ([]byte, error) {
-	payerSalt := msg["payerSalt"].(string)
-	alpha, err := uint256.FromHex(payerSalt)
+	rand32, pi, err := genRand32(privateKey, msg)
 	if err != nil {
 		return nil, err
 	}
 
+	if prob32 < rand32 {
+		return nil, NewVRFLostError(prob32, rand32)
+	}
+	return pi, nil
+}
+
+// genRand32 generates the VRF random number which decides whether the stochastic payment wins
+func genRand32(privateKey *ecdsa.PrivateKey, msg eip712types.TypedDataMessage) (uint32, []byte, error) {
+	// the alpha is the payer salt of AB mode, or payerSalt_pk0 of SR mode
+	payerSalt, ok := msg["payerSalt"].(string)
+	if !ok {
+		payerSalt = msg["payerSalt_pk0"].(string)
+	}
+	alpha, err := uint256.FromHex(payerSalt)
+	if err != nil {
+		return 0, nil, err
+	}
+
 	alphaBytes := alpha.PaddedBytes(32)
-	fmt.Printf("alpha: %v\n", alphaBytes)
 
 	betaBytes, pi, err := ecvrf.NewSecp256k1Sha256Tai().Prove(privateKey, alphaBytes)
 	if len(betaBytes) != 32 {
-		return nil, errors.New("invalid VRF beta bytes")
+		return 0, nil, errors.New("invalid VRF beta bytes")
 	}
 
 	var rand32Bz [4]byte
@@ -314,12 +513,7 @@ func (backend *Backend) checkProb32(privateKey *ecdsa.PrivateKey, msg eip712type
 	rand32Bz[2] = betaBytes[1]
 	rand32Bz[3] = betaBytes[0]
 	rand32 := binary.BigEndian.Uint32(rand32Bz[:])
-	fmt.Printf("rand32: %v\n", rand32)
-
-	if prob32 < rand32 {
-		return nil, fmt.Errorf("check probability failed: %v < %v", prob32, rand32)
-	}
-	return pi, nil
+	return rand32, pi, nil
 }
 
 func (backend *Backend) checkAuth(sp *pb.StochasticPayment, b *pb.Bulletin, ap *pb.AuthProof) error {
@@ -344,11 +538,11 @@ func (backend *Backend) checkAuth(sp *pb.StochasticPayment, b *pb.Bulletin, ap *
 
 		acHash := sha256.Sum256(acBz)
 		if !bytes.Equal(sourceAcHash[:], acHash[:]) {
-			return errors.New("incorrect auth challenge hash")
+			return NewAuthFailedError(AuthConditionChallengeHash, "incorrect auth challenge hash")
 		}
 
-		if !ap.CheckAuthProof(sp, b) {
-			return errors.New("incorrect auth proof")
+		if !ap.CheckAuthProof(sp, b, backend.chains) {
+			return NewAuthFailedError(AuthConditionProof, "incorrect auth proof")
 		}
 	}
 
@@ -358,22 +552,19 @@ func (backend *Backend) checkAuth(sp *pb.StochasticPayment, b *pb.Bulletin, ap *
 // ----------------------------------------------------------------
 
 func (backend *Backend) GetBulletinByGanyUrl(ganyUrlBz []byte) (*pb.Bulletin, error) {
-	tx, err := backend.GetGanyTxByGanyUrl(ganyUrlBz)
-	if err != nil {
-		return nil, err
-	}
-	return tx.GetBulletin()
+	_, b, err := backend.getCachedGanyTx(ganyUrlBz)
+	return b, err
 }
 
 func (backend *Backend) GetGanyTxByGanyUrl(ganyUrlBz []byte) (pb.GanyTx, error) {
-	shardIndex := binary.BigEndian.Uint32(ganyUrlBz[:4]) % backend.numOfShards
-	return backend.apps[shardIndex].GetGanyTxByUrl(ganyUrlBz)
+	tx, _, err := backend.getCachedGanyTx(ganyUrlBz)
+	return tx, err
 }
 
 func (backend *Backend) QueryBulletinByTimePeriod(typ pb.Bulletin_BulletinType, topicHash [32]byte, start, end int64,
 	excludeSNs map[string]struct{}) ([]*pb.Bulletin, error) {
 
-	shardIndex := binary.BigEndian.Uint32(topicHash[:4]) % backend.numOfShards
+	shardIndex := backend.shardMap.ShardOf(topicHash[:])
 	return backend.apps[shardIndex].QueryBulletinByTimePeriod(typ, topicHash, start, end, excludeSNs)
 }
 
@@ -387,6 +578,63 @@ func (backend *Backend) LoadWalletInStochasticPay(tokenAddr, ownerAddr gethcmn.A
 	return backend.follower.LoadWalletInStochasticPay(tokenAddr, ownerAddr)
 }
 
+func (backend *Backend) GetSyncStatus() follower.SyncStatus {
+	return backend.follower.GetSyncStatus()
+}
+
+func (backend *Backend) GetUpstreamStats() []web3client.EndpointStats {
+	return backend.sbchClient.GetEndpointStats()
+}
+
+func (backend *Backend) checkFollowerLag() error {
+	if backend.config.MaxFollowerLag <= 0 {
+		return nil
+	}
+	if status := backend.follower.GetSyncStatus(); status.Lag > backend.config.MaxFollowerLag {
+		return NewFollowerLaggingError(status, backend.config.MaxFollowerLag)
+	}
+	return nil
+}
+
+// GetWalletEvents returns the deposits, withdrawals and payments of a wallet, to trace the disputes
+func (backend *Backend) GetWalletEvents(owner, token gethcmn.Address, startHeight, endHeight int64) ([]*follower.WalletEvent, error) {
+	return backend.follower.GetWalletEvents(owner, token, startHeight, endHeight, follower.MaxWalletEventsPerQuery)
+}
+
+// EthCall runs a read-only call on the followed smartBCH state, within the gas cap and the concurrent slots
+// of the config. The follower cannot apply the next block until the calls on its state return.
+func (backend *Backend) EthCall(msg geth.CallMsg, height int64) (*follower.CallResult, error) {
+	if msg.Gas == 0 || msg.Gas > backend.config.CallGasCap {
+		msg.Gas = backend.config.CallGasCap
+	}
+
+	timer := time.NewTimer(backend.config.CallQueueTimeout)
+	defer timer.Stop()
+	select {
+	case backend.callSlots <- struct{}{}:
+		defer func() { <-backend.callSlots }()
+	case <-timer.C:
+		return nil, NewRateLimitedError("call", "concurrent", backend.config.CallQueueTimeout)
+	}
+	return backend.follower.EthCall(msg, height)
+}
+
 func (backend *Backend) GetValidatorPubKeyList() [][]byte {
 	return backend.follower.GetValidatorPubKeyList()
 }
+
+func (backend *Backend) GetValidators() []*follower.ValidatorInfo {
+	return backend.follower.GetValidators()
+}
+
+func (backend *Backend) GetValidatorSetAt(height int64) (*follower.ValidatorSet, error) {
+	return backend.follower.GetValidatorSetAt(height)
+}
+
+func (backend *Backend) GetValidatorSetByRoot(root []byte) (*follower.ValidatorSet, error) {
+	return backend.follower.GetValidatorSetByRoot(root)
+}
+
+func (backend *Backend) GetValidatorSetByElectedTime(electedTime int64) (*follower.ValidatorSet, error) {
+	return backend.follower.GetValidatorSetByElectedTime(electedTime)
+}
@@ -0,0 +1,165 @@
+package backend
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+// BulletinCacheStats are the counters of the bulletin cache since the node started
+type BulletinCacheStats struct {
+	Size          int    `json:"size"`
+	Capacity      int    `json:"capacity"`
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`     // dropped as the least recently used
+	Invalidations uint64 `json:"invalidations"` // dropped as overwritten, deleted or expired
+}
+
+type cachedBulletin struct {
+	ganyUrl  string
+	tx       pb.GanyTx
+	bulletin *pb.Bulletin
+	expireAt time.Time
+}
+
+// bulletinCache is a LRU cache of the decoded bulletins keyed by gany URL.
+// The entries are invalidated by the overwrite events of the shards, and they live at most `ttl`,
+// so the bulletins expired in the shards are not served for long.
+type bulletinCache struct {
+	mtx      sync.Mutex
+	capacity int // zero means disabled
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	lru      *list.List // the front is the most recently used
+	stats    BulletinCacheStats
+	now      func() time.Time
+
+	// bumped by each invalidation, a bulletin read from the shard before it is not added
+	generation uint64
+}
+
+func newBulletinCache(capacity int, ttl time.Duration) *bulletinCache {
+	return &bulletinCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		now:      time.Now,
+	}
+}
+
+// get returns the generation to add the bulletin with on a miss
+func (c *bulletinCache) get(ganyUrl []byte) (pb.GanyTx, *pb.Bulletin, uint64, bool) {
+	if c.capacity <= 0 {
+		return nil, nil, 0, false
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	elem, ok := c.entries[string(ganyUrl)]
+	if ok && c.now().After(elem.Value.(*cachedBulletin).expireAt) {
+		c.remove(elem)
+		c.stats.Invalidations++
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, nil, c.generation, false
+	}
+
+	c.stats.Hits++
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*cachedBulletin)
+	return entry.tx, entry.bulletin, c.generation, true
+}
+
+func (c *bulletinCache) add(ganyUrl []byte, tx pb.GanyTx, b *pb.Bulletin, generation uint64) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	now := c.now()
+	expireAt := now.Add(c.ttl)
+	if duration := time.Unix(b.GetDuration(), 0); duration.After(now) && duration.Before(expireAt) {
+		expireAt = duration
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if generation != c.generation {
+		return // the bulletin may have been overwritten after it was read
+	}
+	if elem, ok := c.entries[string(ganyUrl)]; ok {
+		c.remove(elem)
+	}
+	for c.lru.Len() >= c.capacity {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+	c.entries[string(ganyUrl)] = c.lru.PushFront(&cachedBulletin{
+		ganyUrl:  string(ganyUrl),
+		tx:       tx,
+		bulletin: b,
+		expireAt: expireAt,
+	})
+}
+
+// invalidate is the OverwriteListener of the shards
+func (c *bulletinCache) invalidate(ganyUrls [][]byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.generation++
+	for _, ganyUrl := range ganyUrls {
+		if elem, ok := c.entries[string(ganyUrl)]; ok {
+			c.remove(elem)
+			c.stats.Invalidations++
+		}
+	}
+}
+
+func (c *bulletinCache) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cachedBulletin).ganyUrl)
+}
+
+func (c *bulletinCache) getStats() BulletinCacheStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	stats := c.stats
+	stats.Size = c.lru.Len()
+	stats.Capacity = c.capacity
+	return stats
+}
+
+// ----------------------------------------------------------------
+
+func (backend *Backend) GetBulletinCacheStats() BulletinCacheStats {
+	return backend.bulletinCache.getStats()
+}
+
+// getCachedGanyTx reads through the bulletin cache
+func (backend *Backend) getCachedGanyTx(ganyUrlBz []byte) (pb.GanyTx, *pb.Bulletin, error) {
+	tx, b, generation, ok := backend.bulletinCache.get(ganyUrlBz)
+	if ok {
+		return tx, b, nil
+	}
+
+	shardIndex := backend.shardMap.ShardOf(ganyUrlBz[:4])
+	tx, err := backend.apps[shardIndex].GetGanyTxByUrl(ganyUrlBz)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err = tx.GetBulletin()
+	if err != nil {
+		return nil, nil, err
+	}
+	backend.bulletinCache.add(ganyUrlBz, tx, b, generation)
+	return tx, b, nil
+}
@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+func TestBulletinCache(t *testing.T) {
+	now := time.Unix(1000, 0)
+	cache := newBulletinCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	url1, url2, url3 := []byte{1}, []byte{2}, []byte{3}
+	b := &pb.Bulletin{Duration: now.Add(time.Hour).Unix()}
+	addMiss := func(url []byte) {
+		_, _, generation, ok := cache.get(url)
+		require.False(t, ok)
+		cache.add(url, pb.GanyTx{url[0]}, b, generation)
+	}
+
+	addMiss(url1)
+	addMiss(url2)
+	tx, cached, _, ok := cache.get(url1)
+	require.True(t, ok)
+	require.Equal(t, pb.GanyTx{1}, tx)
+	require.Same(t, b, cached)
+
+	// url2 is the least recently used
+	addMiss(url3)
+	_, _, _, ok = cache.get(url2)
+	require.False(t, ok)
+	_, _, _, ok = cache.get(url1)
+	require.True(t, ok)
+
+	// overwritten
+	cache.invalidate([][]byte{url1})
+	_, _, generation, ok := cache.get(url1)
+	require.False(t, ok)
+
+	// overwritten again after the shard is read, the old version is not cached
+	cache.invalidate([][]byte{url1})
+	cache.add(url1, pb.GanyTx{1}, b, generation)
+	_, _, _, ok = cache.get(url1)
+	require.False(t, ok)
+
+	// expired
+	now = now.Add(time.Minute + time.Second)
+	_, _, _, ok = cache.get(url3)
+	require.False(t, ok)
+
+	require.Equal(t, BulletinCacheStats{
+		Size:          0,
+		Capacity:      2,
+		Hits:          2,
+		Misses:        7,
+		Evictions:     1,
+		Invalidations: 2,
+	}, cache.getStats())
+
+	// disabled
+	cache = newBulletinCache(0, time.Minute)
+	cache.add(url1, pb.GanyTx{1}, b, 0)
+	_, _, _, ok = cache.get(url1)
+	require.False(t, ok)
+}
@@ -0,0 +1,47 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartbch/ganychain/follower"
+)
+
+type blockingFollower struct {
+	follower.FollowerService
+	gas     chan uint64
+	release chan struct{}
+}
+
+func (f *blockingFollower) EthCall(msg geth.CallMsg, height int64) (*follower.CallResult, error) {
+	f.gas <- msg.Gas
+	<-f.release
+	return &follower.CallResult{GasUsed: msg.Gas}, nil
+}
+
+func TestEthCallBounds(t *testing.T) {
+	config := DefaultConfig()
+	config.CallQueueTimeout = 50 * time.Millisecond
+	f := &blockingFollower{gas: make(chan uint64, 2), release: make(chan struct{})}
+	backend := &Backend{follower: f, config: config, callSlots: make(chan struct{}, 1)}
+
+	// the gas is capped, and the call holds the only slot until it returns
+	done := make(chan error)
+	go func() {
+		_, err := backend.EthCall(geth.CallMsg{Gas: config.CallGasCap * 10}, follower.LatestHeight)
+		done <- err
+	}()
+	require.Equal(t, config.CallGasCap, <-f.gas)
+
+	_, err := backend.EthCall(geth.CallMsg{}, follower.LatestHeight)
+	require.Equal(t, ErrCodeRateLimited, err.(*Error).Code)
+
+	close(f.release)
+	require.NoError(t, <-done)
+	result, err := backend.EthCall(geth.CallMsg{Gas: 21000}, follower.LatestHeight)
+	require.NoError(t, err)
+	require.EqualValues(t, 21000, result.GasUsed)
+}
@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/holiman/uint256"
+
+	"github.com/smartbch/ganychain/follower"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+// the cached results of a chain are dropped once there are more than this in a block
+const maxCachedCalls = 10000
+
+// ChainConfig is an EVM chain whose contracts are called to check the dynamic set proofs
+type ChainConfig struct {
+	ChainId uint64 `mapstructure:"chain-id"`
+	RpcUrl  string `mapstructure:"rpc-url"`
+}
+
+// EVMClient is the part of ethclient.Client used to call a chain, the simulated backend implements it too
+type EVMClient interface {
+	bind.ContractCaller
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+}
+
+// ChainRegistry maps the chain IDs to the callers of the chains
+type ChainRegistry struct {
+	callers map[uint256.Int]pb.DynamicSetCaller
+}
+
+var _ pb.DynamicSetCallers = (*ChainRegistry)(nil)
+
+// NewChainRegistry keeps the first caller of each chain ID
+func NewChainRegistry(callers ...pb.DynamicSetCaller) *ChainRegistry {
+	r := &ChainRegistry{callers: make(map[uint256.Int]pb.DynamicSetCaller)}
+	for _, caller := range callers {
+		if _, ok := r.callers[*caller.GetChainId()]; !ok {
+			r.callers[*caller.GetChainId()] = caller
+		}
+	}
+	return r
+}
+
+// newChainRegistry registers smartBCH, which is called on the followed state, and the configured chains
+func newChainRegistry(follower follower.FollowerService, config *Config) *ChainRegistry {
+	callers := []pb.DynamicSetCaller{follower}
+	for _, chain := range config.Chains {
+		client, err := ethclient.Dial(chain.RpcUrl)
+		if err != nil {
+			panic(err)
+		}
+		callers = append(callers, NewEVMChain(uint256.NewInt(chain.ChainId), client, config.ChainCallTimeout))
+	}
+	return NewChainRegistry(callers...)
+}
+
+func (r *ChainRegistry) GetCaller(chainId *uint256.Int) pb.DynamicSetCaller {
+	if r == nil {
+		return nil
+	}
+	return r.callers[*chainId]
+}
+
+// ----------------------------------------------------------------
+
+// evmChain calls the contracts of a chain by JSON-RPC on its latest block,
+// the results are cached until the chain moves to the next block.
+type evmChain struct {
+	chainId *uint256.Int
+	client  EVMClient
+	timeout time.Duration
+
+	mtx    sync.Mutex
+	height uint64 // the height of the cached results
+	cache  map[string][]byte
+}
+
+func NewEVMChain(chainId *uint256.Int, client EVMClient, timeout time.Duration) pb.DynamicSetCaller {
+	return &evmChain{
+		chainId: chainId,
+		client:  client,
+		timeout: timeout,
+		cache:   make(map[string][]byte),
+	}
+}
+
+func (c *evmChain) GetChainId() *uint256.Int {
+	return c.chainId.Clone()
+}
+
+func (c *evmChain) CallContract(from, to gethcmn.Address, data []byte) ([]byte, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	header, err := c.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	height := header.Number.Uint64()
+	key := string(from.Bytes()) + string(to.Bytes()) + string(data)
+	if outData, ok := c.getCached(height, key); ok {
+		return outData, int64(header.Time), nil
+	}
+
+	outData, err := c.client.CallContract(ctx, ethereum.CallMsg{From: from, To: &to, Data: data}, header.Number)
+	if err != nil {
+		return nil, 0, err
+	}
+	c.setCached(height, key, outData)
+	return outData, int64(header.Time), nil
+}
+
+func (c *evmChain) getCached(height uint64, key string) ([]byte, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height != c.height {
+		return nil, false
+	}
+	outData, ok := c.cache[key]
+	return outData, ok
+}
+
+func (c *evmChain) setCached(height uint64, key string, outData []byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height < c.height {
+		return
+	}
+	if height > c.height || len(c.cache) >= maxCachedCalls {
+		c.height = height
+		c.cache = make(map[string][]byte)
+	}
+	c.cache[key] = outData
+}
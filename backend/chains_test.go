@@ -0,0 +1,93 @@
+package backend
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcore "github.com/ethereum/go-ethereum/core"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+// returns uint256(1) for any call
+var constantOneCode = gethcmn.FromHex("0x600a600c600039600a6000f3" + "600160005260206000f3")
+
+type countingClient struct {
+	*backends.SimulatedBackend
+	calls int
+}
+
+func (c *countingClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.calls++
+	return c.SimulatedBackend.CallContract(ctx, call, blockNumber)
+}
+
+func TestEVMChain(t *testing.T) {
+	key, _ := gethcrypto.GenerateKey()
+	deployer := gethcrypto.PubkeyToAddress(key.PublicKey)
+	sim := backends.NewSimulatedBackend(gethcore.GenesisAlloc{deployer: {Balance: big.NewInt(1e18)}}, 10000000)
+	defer sim.Close()
+
+	gasPrice, err := sim.SuggestGasPrice(context.Background())
+	require.NoError(t, err)
+	tx, err := gethtypes.SignTx(gethtypes.NewContractCreation(0, big.NewInt(0), 100000, gasPrice, constantOneCode),
+		gethtypes.LatestSignerForChainID(big.NewInt(1337)), key)
+	require.NoError(t, err)
+	require.NoError(t, sim.SendTransaction(context.Background(), tx))
+	sim.Commit()
+	contractAddr := gethcrypto.CreateAddress(deployer, 0)
+
+	client := &countingClient{SimulatedBackend: sim}
+	registry := NewChainRegistry(NewEVMChain(uint256.NewInt(1337), client, time.Second))
+	require.Nil(t, registry.GetCaller(uint256.NewInt(1)))
+	chain := registry.GetCaller(uint256.NewInt(1337))
+	require.NotNil(t, chain)
+
+	header, err := sim.HeaderByNumber(context.Background(), nil)
+	require.NoError(t, err)
+	outData, blockTime, err := chain.CallContract(deployer, contractAddr, []byte{0x12, 0x34, 0x56, 0x78})
+	require.NoError(t, err)
+	require.Equal(t, gethcmn.LeftPadBytes([]byte{1}, 32), outData)
+	require.EqualValues(t, header.Time, blockTime)
+
+	// cached in the same block
+	_, _, err = chain.CallContract(deployer, contractAddr, []byte{0x12, 0x34, 0x56, 0x78})
+	require.NoError(t, err)
+	require.Equal(t, 1, client.calls)
+
+	sim.Commit()
+	_, _, err = chain.CallContract(deployer, contractAddr, []byte{0x12, 0x34, 0x56, 0x78})
+	require.NoError(t, err)
+	require.Equal(t, 2, client.calls)
+
+	// the dynamic set proof of the chain is checked by calling it
+	ap := &pb.AuthProof{
+		DynamicSetProofList: []*pb.DynamicSetProof{
+			{
+				ChainId:           big.NewInt(1337).Bytes(),
+				TargetContract:    contractAddr.Bytes(),
+				FunctionSelector:  0x12345678,
+				OutData:           outData,
+				MaxTimeDifference: 60,
+				Timestamp:         int64(header.Time),
+			},
+		},
+		OrOfAndOfConditions: []*pb.AndOfConditions{
+			{ConditionNumbers: []int32{1}},
+			{ConditionNumbers: nil},
+			{ConditionNumbers: nil},
+		},
+	}
+	b := &pb.Bulletin{From: deployer.Bytes()}
+	require.True(t, ap.CheckAuthProof(&pb.StochasticPayment{}, b, registry))
+	require.False(t, ap.CheckAuthProof(&pb.StochasticPayment{}, b, NewChainRegistry()))
+}
@@ -0,0 +1,98 @@
+package backend
+
+import "time"
+
+const (
+	DefaultGasLimitMultiplier = 1.2
+	DefaultMaxGasLimit        = 8000000
+	DefaultGasPriceMultiplier = 1.1
+	DefaultMinGasPrice        = 1050000000  // 1.05 gwei
+	DefaultMaxGasPrice        = 50000000000 // 50 gwei
+
+	DefaultShardQueryTimeout = 3 * time.Second
+	DefaultMaxQueryTimeSpan  = 7 * 24 * time.Hour
+
+	DefaultValidatorRootGracePeriod = 10 * time.Minute
+
+	DefaultChainCallTimeout = 3 * time.Second
+
+	DefaultMaxConcurrentCalls = 4
+	DefaultCallGasCap         = 2000000
+	DefaultCallQueueTimeout   = 3 * time.Second
+
+	DefaultMaxFollowerLag = 10 // blocks
+
+	DefaultBulletinCacheSize = 10000
+	DefaultBulletinCacheTTL  = 10 * time.Minute
+
+	DefaultAddressRate  = 1.0 // bulletins per second
+	DefaultAddressBurst = 10
+	DefaultIPRate       = 5.0
+	DefaultIPBurst      = 20
+)
+
+type Config struct {
+	// gas config of the settlement transactions (payToAB, etc.)
+	GasLimitMultiplier float64 `mapstructure:"gas-limit-multiplier"` // applied to the result of EstimateGas
+	MaxGasLimit        uint64  `mapstructure:"max-gas-limit"`
+	GasPriceMultiplier float64 `mapstructure:"gas-price-multiplier"` // applied to the result of SuggestGasPrice
+	MinGasPrice        uint64  `mapstructure:"min-gas-price"`        // in wei
+	MaxGasPrice        uint64  `mapstructure:"max-gas-price"`        // in wei
+
+	// the timeout of each shard in a fan-out query
+	ShardQueryTimeout time.Duration `mapstructure:"shard-query-timeout"`
+	// the max time window of a fan-out query, zero means no limit
+	MaxQueryTimeSpan time.Duration `mapstructure:"max-query-time-span"`
+
+	// the payments signed for the previous validator set are accepted within this period after an election
+	ValidatorRootGracePeriod time.Duration `mapstructure:"validator-root-grace-period"`
+
+	// the rate limits of PutBulletin, the address limit applies to both Bulletin.From and the recovered signer
+	AddressRateLimit RateLimit `mapstructure:"address-rate-limit"`
+	IPRateLimit      RateLimit `mapstructure:"ip-rate-limit"`
+	// keyed by the lower case bulletin type, e.g. "comment", overrides the limits above for this type
+	TypeRateLimits map[string]RateLimit `mapstructure:"type-rate-limits"`
+
+	// the other EVM chains which the dynamic set proofs can be checked on, smartBCH is always checked on the followed state
+	Chains           []ChainConfig `mapstructure:"chains"`
+	ChainCallTimeout time.Duration `mapstructure:"chain-call-timeout"`
+
+	// the bounds of gany_call, which holds the followed state until the EVM returns. The EVM cannot be interrupted,
+	// so a call is bounded by the gas cap, and the calls waiting for one of the slots fail after the queue timeout.
+	MaxConcurrentCalls int           `mapstructure:"max-concurrent-calls"`
+	CallGasCap         uint64        `mapstructure:"call-gas-cap"`
+	CallQueueTimeout   time.Duration `mapstructure:"call-queue-timeout"`
+
+	// the hex private key file of the authenticator, which signs the dynamic set proofs.
+	// The authenticator RPC namespace is available only if it is set.
+	AuthenticatorKeyFile string `mapstructure:"authenticator-key-file"`
+
+	// PutBulletin is refused if the follower is more blocks behind the smartBCH node than this, zero means no limit
+	MaxFollowerLag int64 `mapstructure:"max-follower-lag"`
+
+	// the LRU cache of the decoded bulletins read by gany URL, zero size disables it
+	BulletinCacheSize int           `mapstructure:"bulletin-cache-size"`
+	BulletinCacheTTL  time.Duration `mapstructure:"bulletin-cache-ttl"`
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		GasLimitMultiplier:       DefaultGasLimitMultiplier,
+		MaxGasLimit:              DefaultMaxGasLimit,
+		GasPriceMultiplier:       DefaultGasPriceMultiplier,
+		MinGasPrice:              DefaultMinGasPrice,
+		MaxGasPrice:              DefaultMaxGasPrice,
+		ShardQueryTimeout:        DefaultShardQueryTimeout,
+		MaxQueryTimeSpan:         DefaultMaxQueryTimeSpan,
+		ValidatorRootGracePeriod: DefaultValidatorRootGracePeriod,
+		ChainCallTimeout:         DefaultChainCallTimeout,
+		MaxConcurrentCalls:       DefaultMaxConcurrentCalls,
+		CallGasCap:               DefaultCallGasCap,
+		CallQueueTimeout:         DefaultCallQueueTimeout,
+		MaxFollowerLag:           DefaultMaxFollowerLag,
+		BulletinCacheSize:        DefaultBulletinCacheSize,
+		BulletinCacheTTL:         DefaultBulletinCacheTTL,
+		AddressRateLimit:         RateLimit{Rate: DefaultAddressRate, Burst: DefaultAddressBurst},
+		IPRateLimit:              RateLimit{Rate: DefaultIPRate, Burst: DefaultIPBurst},
+	}
+}
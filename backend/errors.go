@@ -0,0 +1,277 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/holiman/uint256"
+
+	"github.com/smartbch/ganychain/follower"
+)
+
+// The JSON-RPC error codes returned by the gany namespace. They are stable, so the SDKs can branch on them.
+const (
+	ErrCodeInvalidTx           = -32010
+	ErrCodeBadSignature        = -32011
+	ErrCodeInsufficientBalance = -32012
+	ErrCodeVRFLost             = -32013
+	ErrCodeAuthFailed          = -32014
+	ErrCodeShardUnavailable    = -32015
+	ErrCodeSettlementFailed    = -32016
+	ErrCodeRateLimited         = -32017
+	ErrCodeUnderpriced         = -32018
+	ErrCodeStaleValidatorRoot  = -32019
+	ErrCodePaymentDue          = -32020
+	ErrCodeTokenNotAccepted    = -32021
+	ErrCodeFollowerLagging     = -32022
+	ErrCodeInvalidParams       = -32023
+)
+
+var (
+	_ gethrpc.Error     = (*Error)(nil)
+	_ gethrpc.DataError = (*Error)(nil)
+)
+
+// Error implements rpc.Error and rpc.DataError of go-ethereum,
+// so its code and data are sent to the clients as they are.
+type Error struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) ErrorCode() int {
+	return e.Code
+}
+
+func (e *Error) ErrorData() interface{} {
+	return e.Data
+}
+
+// ErrorCodeOf returns the JSON-RPC error code of err, or 0 if err is not a typed error.
+func ErrorCodeOf(err error) int {
+	if e, ok := err.(*Error); ok {
+		return e.Code
+	}
+	return 0
+}
+
+// ----------------------------------------------------------------
+
+type InvalidTxData struct {
+	AbciCode uint32 `json:"abciCode,omitempty"` // CheckTx/DeliverTx code of the gany app
+	Log      string `json:"log,omitempty"`
+}
+
+func NewInvalidTxError(err error) *Error {
+	return &Error{
+		Code:    ErrCodeInvalidTx,
+		Message: "invalid tx: " + err.Error(),
+	}
+}
+
+func NewRejectedTxError(abciCode uint32, log string) *Error {
+	return &Error{
+		Code:    ErrCodeInvalidTx,
+		Message: fmt.Sprintf("tx rejected by gany app: code: %v, error: %v", abciCode, log),
+		Data:    InvalidTxData{AbciCode: abciCode, Log: log},
+	}
+}
+
+type BadSignatureData struct {
+	Signer        string `json:"signer,omitempty"`
+	DelegatedAddr string `json:"delegatedAddr,omitempty"`
+}
+
+func NewBadSignatureError(msg string, data *BadSignatureData) *Error {
+	e := &Error{
+		Code:    ErrCodeBadSignature,
+		Message: "bad signature: " + msg,
+	}
+	if data != nil {
+		e.Data = data
+	}
+	return e
+}
+
+type InsufficientBalanceData struct {
+	Address  string `json:"address"`
+	Balance  string `json:"balance"`            // decimal
+	Required string `json:"required"`           // decimal, amountToPayee + amountToValidator
+	Reserved string `json:"reserved,omitempty"` // decimal, held by the payments in flight
+}
+
+func NewInsufficientBalanceError(data InsufficientBalanceData) *Error {
+	return &Error{
+		Code:    ErrCodeInsufficientBalance,
+		Message: "insufficient balance",
+		Data:    data,
+	}
+}
+
+type VRFLostData struct {
+	Prob32 uint32 `json:"prob32"`
+	Rand32 uint32 `json:"rand32"`
+}
+
+func NewVRFLostError(prob32, rand32 uint32) *Error {
+	return &Error{
+		Code:    ErrCodeVRFLost,
+		Message: fmt.Sprintf("check probability failed: %v < %v", prob32, rand32),
+		Data:    VRFLostData{Prob32: prob32, Rand32: rand32},
+	}
+}
+
+// The failing conditions of the auth check
+const (
+	AuthConditionChallengeHash = "auth-challenge-hash"
+	AuthConditionProof         = "auth-proof"
+)
+
+type AuthFailedData struct {
+	Condition string `json:"condition"`
+}
+
+func NewAuthFailedError(condition, msg string) *Error {
+	return &Error{
+		Code:    ErrCodeAuthFailed,
+		Message: msg,
+		Data:    AuthFailedData{Condition: condition},
+	}
+}
+
+type ShardUnavailableData struct {
+	FailedShards []ShardFailure `json:"failedShards"`
+}
+
+func NewShardUnavailableError(failures []ShardFailure) *Error {
+	msg := "shard unavailable"
+	if len(failures) != 0 {
+		msg = fmt.Sprintf("shard %v unavailable: %v", failures[0].Shard, failures[0].Error)
+	}
+	return &Error{
+		Code:    ErrCodeShardUnavailable,
+		Message: msg,
+		Data:    ShardUnavailableData{FailedShards: failures},
+	}
+}
+
+type SettlementFailedData struct {
+	TxHash string `json:"txHash,omitempty"`
+}
+
+func NewSettlementFailedError(txHash string, err error) *Error {
+	return &Error{
+		Code:    ErrCodeSettlementFailed,
+		Message: "settlement failed: " + err.Error(),
+		Data:    SettlementFailedData{TxHash: txHash},
+	}
+}
+
+type RateLimitedData struct {
+	Key        string `json:"key"` // from, signer, ip or call
+	Id         string `json:"id"`
+	RetryAfter int64  `json:"retryAfter"` // in milliseconds
+}
+
+func NewRateLimitedError(key, id string, retryAfter time.Duration) *Error {
+	return &Error{
+		Code:    ErrCodeRateLimited,
+		Message: fmt.Sprintf("rate limited: too many requests of %v %v", key, id),
+		Data:    RateLimitedData{Key: key, Id: id, RetryAfter: retryAfter.Milliseconds()},
+	}
+}
+
+type UnderpricedData struct {
+	ExpectedValue string `json:"expectedValue"` // decimal, in the smallest unit of the token
+	MinPrice      string `json:"minPrice"`      // decimal, in the smallest unit of the token
+}
+
+func NewUnderpricedError(expectedValue, minPrice *uint256.Int) *Error {
+	return &Error{
+		Code:    ErrCodeUnderpriced,
+		Message: "underpriced: expected value of the payment is below the minimum price",
+		Data: UnderpricedData{
+			ExpectedValue: expectedValue.ToBig().String(),
+			MinPrice:      minPrice.ToBig().String(),
+		},
+	}
+}
+
+type StaleValidatorRootData struct {
+	Root        string `json:"root"`
+	ElectedRoot string `json:"electedRoot"` // the payment should be signed for this root
+}
+
+func NewStaleValidatorRootError(root, electedRoot []byte) *Error {
+	return &Error{
+		Code:    ErrCodeStaleValidatorRoot,
+		Message: "validator public key root is not of the elected validator set",
+		Data: StaleValidatorRootData{
+			Root:        hexutil.Encode(root),
+			ElectedRoot: hexutil.Encode(electedRoot),
+		},
+	}
+}
+
+type PaymentDueData struct {
+	DueTime   int64 `json:"dueTime"`
+	BlockTime int64 `json:"blockTime"` // of the latest smartBCH block
+	MinWindow int64 `json:"minWindow"` // in seconds, the payment must be due later than blockTime + minWindow
+}
+
+func NewPaymentDueError(dueTime, blockTime int64, minWindow time.Duration) *Error {
+	return &Error{
+		Code:    ErrCodePaymentDue,
+		Message: "payment is due or will be due before it can be settled",
+		Data: PaymentDueData{
+			DueTime:   dueTime,
+			BlockTime: blockTime,
+			MinWindow: int64(minWindow / time.Second),
+		},
+	}
+}
+
+type TokenNotAcceptedData struct {
+	Token string `json:"token"`
+}
+
+func NewTokenNotAcceptedError(token gethcmn.Address) *Error {
+	return &Error{
+		Code:    ErrCodeTokenNotAccepted,
+		Message: "token of the payment is not accepted",
+		Data:    TokenNotAcceptedData{Token: token.Hex()},
+	}
+}
+
+type FollowerLaggingData struct {
+	SyncedHeight   int64 `json:"syncedHeight"`
+	UpstreamHeight int64 `json:"upstreamHeight"`
+	MaxLag         int64 `json:"maxLag"`
+}
+
+func NewFollowerLaggingError(status follower.SyncStatus, maxLag int64) *Error {
+	return &Error{
+		Code:    ErrCodeFollowerLagging,
+		Message: fmt.Sprintf("smartBCH follower is %d blocks behind, the balances cannot be checked", status.Lag),
+		Data: FollowerLaggingData{
+			SyncedHeight:   status.SyncedHeight,
+			UpstreamHeight: status.UpstreamHeight,
+			MaxLag:         maxLag,
+		},
+	}
+}
+
+func NewInvalidParamsError(msg string) *Error {
+	return &Error{
+		Code:    ErrCodeInvalidParams,
+		Message: "invalid params: " + msg,
+	}
+}
@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/smartbch/ganychain/app"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+// ShardFailure reports a shard which did not answer a fan-out query
+type ShardFailure struct {
+	Shard   uint32 `json:"shard"`
+	ChainId string `json:"chainId"`
+	Error   string `json:"error"`
+}
+
+// FanOutResult contains the merged results of all the shards,
+// and the failed shards if some of them are down or time out.
+type FanOutResult struct {
+	Bulletins    []*pb.Bulletin
+	FailedShards []ShardFailure
+}
+
+func (r *FanOutResult) IsPartial() bool {
+	return len(r.FailedShards) != 0
+}
+
+type shardResult struct {
+	shard     uint32
+	bulletins []*pb.Bulletin
+	err       error
+}
+
+func (backend *Backend) QueryBulletinsByAuthor(from []byte, start, end int64) (*FanOutResult, error) {
+	return backend.fanOutQuery(&app.BulletinFilter{
+		From:      from,
+		StartTime: start,
+		EndTime:   end,
+	})
+}
+
+func (backend *Backend) QueryBulletinsByTime(types []pb.Bulletin_BulletinType, start, end int64) (*FanOutResult, error) {
+	return backend.fanOutQuery(&app.BulletinFilter{
+		Types:     types,
+		StartTime: start,
+		EndTime:   end,
+	})
+}
+
+func (backend *Backend) SearchBulletins(keyword []byte, start, end int64) (*FanOutResult, error) {
+	if len(keyword) == 0 {
+		return nil, NewInvalidParamsError("empty keyword")
+	}
+	return backend.fanOutQuery(&app.BulletinFilter{
+		Keyword:   keyword,
+		StartTime: start,
+		EndTime:   end,
+	})
+}
+
+// fanOutQuery queries all the shards in parallel, and merges the results in descending order of timestamp.
+// It only fails when all the shards fail. The scans of the shards which time out are cancelled.
+func (backend *Backend) fanOutQuery(filter *app.BulletinFilter) (*FanOutResult, error) {
+	if filter.StartTime > filter.EndTime {
+		return nil, NewInvalidParamsError(fmt.Sprintf("start time %d is after end time %d", filter.StartTime, filter.EndTime))
+	}
+	maxSpan := backend.config.MaxQueryTimeSpan
+	if maxSpan > 0 && uint64(filter.EndTime)-uint64(filter.StartTime) > uint64(maxSpan/time.Second) {
+		return nil, NewInvalidParamsError(fmt.Sprintf("time span %d seconds exceeds the limit %s",
+			filter.EndTime-filter.StartTime, maxSpan))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backend.config.ShardQueryTimeout)
+	defer cancel()
+
+	resultCh := make(chan shardResult, len(backend.apps)) // buffered, so the late shards will not be blocked
+	for i, a := range backend.apps {
+		go func(shard uint32, a app.GanyApp) {
+			bulletins, err := a.QueryBulletins(ctx, filter)
+			resultCh <- shardResult{shard: shard, bulletins: bulletins, err: err}
+		}(uint32(i), a)
+	}
+
+	result := &FanOutResult{}
+	answered := make([]bool, len(backend.apps))
+
+loop:
+	for received := 0; received < len(backend.apps); received++ {
+		select {
+		case r := <-resultCh:
+			answered[r.shard] = true
+			if errors.Is(r.err, context.DeadlineExceeded) {
+				result.FailedShards = append(result.FailedShards, backend.shardFailure(r.shard, "timeout"))
+				continue
+			} else if r.err != nil {
+				result.FailedShards = append(result.FailedShards, backend.shardFailure(r.shard, r.err.Error()))
+				continue
+			}
+			result.Bulletins = append(result.Bulletins, r.bulletins...)
+		case <-ctx.Done():
+			for shard, ok := range answered {
+				if !ok {
+					result.FailedShards = append(result.FailedShards, backend.shardFailure(uint32(shard), "timeout"))
+				}
+			}
+			break loop
+		}
+	}
+
+	sort.Slice(result.FailedShards, func(i, j int) bool {
+		return result.FailedShards[i].Shard < result.FailedShards[j].Shard
+	})
+	if len(result.FailedShards) == len(backend.apps) {
+		return nil, NewShardUnavailableError(result.FailedShards)
+	}
+	if result.IsPartial() {
+		backend.logger.Error("partial fan-out query result", "failedShards", fmt.Sprintf("%+v", result.FailedShards))
+	}
+
+	sort.SliceStable(result.Bulletins, func(i, j int) bool {
+		return result.Bulletins[i].Timestamp > result.Bulletins[j].Timestamp
+	})
+	if len(result.Bulletins) > app.MaxQueryResultCount {
+		result.Bulletins = result.Bulletins[:app.MaxQueryResultCount]
+	}
+	return result, nil
+}
+
+func (backend *Backend) shardFailure(shard uint32, errStr string) ShardFailure {
+	return ShardFailure{
+		Shard:   shard,
+		ChainId: backend.apps[shard].GetChainId(),
+		Error:   errStr,
+	}
+}
@@ -0,0 +1,101 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/smartbch/ganychain/app"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+type fakeShard struct {
+	app.GanyApp
+	shard     int
+	bulletins []*pb.Bulletin
+	err       error
+	delay     time.Duration
+	cancelled chan struct{}
+}
+
+func (f *fakeShard) GetChainId() string {
+	return fmt.Sprintf("shard%d", f.shard)
+}
+
+func (f *fakeShard) QueryBulletins(ctx context.Context, filter *app.BulletinFilter) ([]*pb.Bulletin, error) {
+	select {
+	case <-ctx.Done():
+		close(f.cancelled)
+		return nil, ctx.Err()
+	case <-time.After(f.delay):
+	}
+	return f.bulletins, f.err
+}
+
+func newFanOutBackend(apps ...app.GanyApp) *Backend {
+	config := DefaultConfig()
+	config.ShardQueryTimeout = 100 * time.Millisecond
+	return &Backend{
+		numOfShards: uint32(len(apps)),
+		apps:        apps,
+		config:      config,
+		logger:      tmlog.NewNopLogger(),
+	}
+}
+
+func TestFanOutQuery(t *testing.T) {
+	b1 := &pb.Bulletin{Timestamp: 100}
+	b2 := &pb.Bulletin{Timestamp: 300}
+	b3 := &pb.Bulletin{Timestamp: 200}
+
+	backend := newFanOutBackend(
+		&fakeShard{shard: 0, bulletins: []*pb.Bulletin{b1, b2}},
+		&fakeShard{shard: 1, bulletins: []*pb.Bulletin{b3}},
+	)
+	result, err := backend.QueryBulletinsByTime(nil, 0, 1000)
+	require.NoError(t, err)
+	require.False(t, result.IsPartial())
+	require.Equal(t, []*pb.Bulletin{b2, b3, b1}, result.Bulletins)
+
+	// one shard is down, another times out
+	backend = newFanOutBackend(
+		&fakeShard{shard: 0, bulletins: []*pb.Bulletin{b1}},
+		&fakeShard{shard: 1, err: errors.New("db closed")},
+		&fakeShard{shard: 2, bulletins: []*pb.Bulletin{b3}, delay: time.Second, cancelled: make(chan struct{})},
+	)
+	result, err = backend.QueryBulletinsByTime(nil, 0, 1000)
+	require.NoError(t, err)
+	require.True(t, result.IsPartial())
+	require.Equal(t, []*pb.Bulletin{b1}, result.Bulletins)
+	require.Equal(t, []ShardFailure{
+		{Shard: 1, ChainId: "shard1", Error: "db closed"},
+		{Shard: 2, ChainId: "shard2", Error: "timeout"},
+	}, result.FailedShards)
+	// the scan of the slow shard is cancelled
+	select {
+	case <-backend.apps[2].(*fakeShard).cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the slow shard is not cancelled")
+	}
+
+	// all shards fail
+	backend = newFanOutBackend(
+		&fakeShard{shard: 0, err: errors.New("db closed")},
+	)
+	_, err = backend.QueryBulletinsByTime(nil, 0, 1000)
+	require.Equal(t, ErrCodeShardUnavailable, ErrorCodeOf(err))
+
+	_, err = backend.SearchBulletins(nil, 0, 1000)
+	require.Equal(t, ErrCodeInvalidParams, ErrorCodeOf(err))
+	_, err = backend.QueryBulletinsByTime(nil, 1000, 0)
+	require.Equal(t, ErrCodeInvalidParams, ErrorCodeOf(err))
+
+	// the time window is too wide
+	_, err = backend.QueryBulletinsByTime(nil, 0, int64(DefaultMaxQueryTimeSpan/time.Second)+1)
+	require.Equal(t, ErrCodeInvalidParams, ErrorCodeOf(err))
+}
@@ -0,0 +1,91 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+)
+
+// setSettlementFee fills the gas limit and gas price of a settlement transaction,
+// according to the estimation and suggestion of the smartBCH node.
+func (backend *Backend) setSettlementFee(ctx context.Context, auth *bind.TransactOpts, to gethcmn.Address, data []byte) error {
+	estimatedGas, err := backend.sbchClient.EstimateGas(ctx, ethereum.CallMsg{
+		From: auth.From,
+		To:   &to,
+		Data: data,
+	})
+	if err != nil {
+		backend.logger.Error("settlement gas estimation failed", "to", to.Hex(), "err", err)
+		return fmt.Errorf("estimate gas failed: %w", err)
+	}
+
+	gasLimit, err := calcGasLimit(estimatedGas, backend.config)
+	if err != nil {
+		backend.logger.Error("settlement gas limit rejected", "estimatedGas", estimatedGas,
+			"maxGasLimit", backend.config.MaxGasLimit, "err", err)
+		return err
+	}
+
+	suggestedGasPrice, err := backend.sbchClient.SuggestGasPrice(ctx)
+	if err != nil {
+		backend.logger.Error("settlement gas price suggestion failed", "err", err)
+		return fmt.Errorf("suggest gas price failed: %w", err)
+	}
+
+	gasPrice := calcGasPrice(suggestedGasPrice, backend.config)
+
+	backend.logger.Info("settlement fee",
+		"to", to.Hex(),
+		"estimatedGas", estimatedGas,
+		"gasLimit", gasLimit,
+		"suggestedGasPrice", suggestedGasPrice.String(),
+		"gasPrice", gasPrice.String(),
+		"minGasPrice", backend.config.MinGasPrice,
+		"maxGasPrice", backend.config.MaxGasPrice)
+
+	auth.GasLimit = gasLimit
+	auth.GasPrice = gasPrice
+	return nil
+}
+
+// calcGasLimit applies the multiplier to the estimated gas, and the result must not exceed the max gas limit.
+func calcGasLimit(estimatedGas uint64, config *Config) (uint64, error) {
+	gasLimit := mulBig(new(big.Int).SetUint64(estimatedGas), config.GasLimitMultiplier).Uint64()
+	if gasLimit < estimatedGas {
+		gasLimit = estimatedGas
+	}
+
+	if gasLimit > config.MaxGasLimit {
+		if estimatedGas > config.MaxGasLimit {
+			return 0, fmt.Errorf("estimated gas %v exceeds max gas limit %v", estimatedGas, config.MaxGasLimit)
+		}
+		gasLimit = config.MaxGasLimit
+	}
+	return gasLimit, nil
+}
+
+// calcGasPrice applies the multiplier to the suggested gas price, and clamps the result into [MinGasPrice, MaxGasPrice].
+func calcGasPrice(suggestedGasPrice *big.Int, config *Config) *big.Int {
+	gasPrice := mulBig(suggestedGasPrice, config.GasPriceMultiplier)
+
+	minGasPrice := new(big.Int).SetUint64(config.MinGasPrice)
+	maxGasPrice := new(big.Int).SetUint64(config.MaxGasPrice)
+	if gasPrice.Cmp(minGasPrice) < 0 {
+		return minGasPrice
+	}
+	if maxGasPrice.Sign() > 0 && gasPrice.Cmp(maxGasPrice) > 0 {
+		return maxGasPrice
+	}
+	return gasPrice
+}
+
+// mulBig returns round(x * multiplier)
+func mulBig(x *big.Int, multiplier float64) *big.Int {
+	product := new(big.Float).Mul(new(big.Float).SetInt(x), big.NewFloat(multiplier))
+	result, _ := product.Add(product, big.NewFloat(0.5)).Int(nil)
+	return result
+}
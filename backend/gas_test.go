@@ -0,0 +1,40 @@
+package backend
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalcGasLimit(t *testing.T) {
+	config := DefaultConfig()
+
+	gasLimit, err := calcGasLimit(100000, config)
+	require.NoError(t, err)
+	require.Equal(t, uint64(120000), gasLimit)
+
+	// capped by the max gas limit
+	gasLimit, err = calcGasLimit(7000000, config)
+	require.NoError(t, err)
+	require.Equal(t, uint64(DefaultMaxGasLimit), gasLimit)
+
+	// the estimation itself exceeds the max gas limit
+	_, err = calcGasLimit(9000000, config)
+	require.Error(t, err)
+}
+
+func TestCalcGasPrice(t *testing.T) {
+	config := DefaultConfig()
+
+	gasPrice := calcGasPrice(big.NewInt(10000000000), config)
+	require.Equal(t, big.NewInt(11000000000), gasPrice)
+
+	// floor
+	gasPrice = calcGasPrice(big.NewInt(1), config)
+	require.Equal(t, new(big.Int).SetUint64(DefaultMinGasPrice), gasPrice)
+
+	// cap
+	gasPrice = calcGasPrice(big.NewInt(100000000000), config)
+	require.Equal(t, new(big.Int).SetUint64(DefaultMaxGasPrice), gasPrice)
+}
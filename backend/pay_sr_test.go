@@ -0,0 +1,52 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartbch/ganychain/contract"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+func TestGenPayToSRParams(t *testing.T) {
+	pubkey := gethcrypto.CompressPubkey(&validatorPrivateKey.PublicKey)
+	sp := &pb.StochasticPayment{
+		ValidatorPubkey: pubkey,
+		DueTime:         time.Now().Add(time.Hour).Unix(),
+		Probability:     0xFFFFFFFF,
+		Nonces:          make([]byte, 32),
+		Payee:           gethcrypto.PubkeyToAddress(validatorPrivateKey.PublicKey).Bytes(),
+		AmountToPayee:   gethcmn.FromHex("0x50"),
+		Signature:       make([]byte, 65),
+	}
+	require.True(t, sp.IsValid())
+	require.True(t, sp.IsSingleReceiver())
+
+	var r, s [32]byte
+	param := genPayToSRParams(sp.GenEIP712MsgForSR(contract.SBCHTokenAddress), r, s, 28)
+
+	stochasticPayABI, err := contract.StochasticPayVRFMetaData.GetAbi()
+	require.NoError(t, err)
+	data, err := stochasticPayABI.Pack("payToSingleReciever", []byte{0x00}, param)
+	require.NoError(t, err)
+	args, err := stochasticPayABI.Methods["payToSingleReciever"].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	packed := *abi.ConvertType(args[1], new(contract.StochasticPayVRFParamsSr)).(*contract.StochasticPayVRFParamsSr)
+
+	// payerSalt_pk0_v = the flagged payer salt (30 bytes) || the prefix of the public key || v
+	payerSaltPk0V := gethcmn.LeftPadBytes(packed.PayerSaltPk0V.Bytes(), 32)
+	payerSalt := gethcrypto.Keccak256([]byte(pb.PayerSaltTemplate))
+	require.Equal(t, 0x80|payerSalt[0], payerSaltPk0V[0])
+	require.Equal(t, payerSalt[1:30], payerSaltPk0V[1:30])
+	require.Equal(t, pubkey[0], payerSaltPk0V[30])
+	require.EqualValues(t, 28, payerSaltPk0V[31])
+
+	// pkTail = the X coordinate of the public key
+	require.Equal(t, pubkey[1:], gethcmn.LeftPadBytes(packed.PkTail.Bytes(), 32))
+	require.Equal(t, 0, packed.PkTail.Cmp(validatorPrivateKey.PublicKey.X))
+}
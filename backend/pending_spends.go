@@ -0,0 +1,105 @@
+package backend
+
+import (
+	"sync"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+type walletKey struct {
+	token gethcmn.Address
+	payer gethcmn.Address
+}
+
+// pendingSpend is the amount of a wallet reserved by a payment in flight
+type pendingSpend struct {
+	key           walletKey
+	amount        *uint256.Int
+	dueTime       int64 // the payment cannot be settled in the blocks after it
+	settledHeight int64 // the smartBCH height of the settlement, 0 if not settled
+}
+
+// pendingSpends tracks the payments which are accepted but not reflected in the followed wallets yet.
+// A reservation is released when the payment fails before it is sent, when the followed state includes its
+// settlement, or when the followed block time passes its due time, which the contract checks against the block time.
+type pendingSpends struct {
+	mtx          sync.Mutex
+	wallets      map[walletKey]map[*pendingSpend]struct{}
+	syncedHeight func() int64 // the height of the followed state
+	blockTime    func() int64 // the time of the latest followed block
+}
+
+func newPendingSpends(syncedHeight, blockTime func() int64) *pendingSpends {
+	return &pendingSpends{
+		wallets:      make(map[walletKey]map[*pendingSpend]struct{}),
+		syncedHeight: syncedHeight,
+		blockTime:    blockTime,
+	}
+}
+
+// reserve holds `amount` of the wallet if the balance minus the reserved amount covers it,
+// it returns the reserved amount before. Nothing is held with dryRun.
+func (p *pendingSpends) reserve(key walletKey, balance, amount *uint256.Int, dueTime int64, dryRun bool) (*pendingSpend, *uint256.Int, bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	reserved := p.reservedLocked(key)
+	required := uint256.NewInt(0).Add(reserved, amount)
+	if balance.Lt(required) || dryRun {
+		return nil, reserved, !balance.Lt(required)
+	}
+
+	spend := &pendingSpend{key: key, amount: amount.Clone(), dueTime: dueTime}
+	if p.wallets[key] == nil {
+		p.wallets[key] = make(map[*pendingSpend]struct{})
+	}
+	p.wallets[key][spend] = struct{}{}
+	return spend, reserved, true
+}
+
+// release drops the reservation of a payment which is not sent
+func (p *pendingSpends) release(spend *pendingSpend) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.removeLocked(spend)
+}
+
+// settle keeps the reservation until the followed state includes the settlement at `height`
+func (p *pendingSpends) settle(spend *pendingSpend, height int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	spend.settledHeight = height
+}
+
+func (p *pendingSpends) reservedOf(key walletKey) *uint256.Int {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.reservedLocked(key)
+}
+
+func (p *pendingSpends) reservedLocked(key walletKey) *uint256.Int {
+	blockTime := p.blockTime()
+	syncedHeight := p.syncedHeight()
+	reserved := uint256.NewInt(0)
+	for spend := range p.wallets[key] {
+		if spend.settledHeight != 0 && spend.settledHeight <= syncedHeight {
+			p.removeLocked(spend) // the balance is updated
+			continue
+		}
+		if spend.settledHeight == 0 && spend.dueTime < blockTime {
+			p.removeLocked(spend) // it cannot be settled anymore
+			continue
+		}
+		reserved.Add(reserved, spend.amount)
+	}
+	return reserved
+}
+
+func (p *pendingSpends) removeLocked(spend *pendingSpend) {
+	spends := p.wallets[spend.key]
+	delete(spends, spend)
+	if len(spends) == 0 {
+		delete(p.wallets, spend.key)
+	}
+}
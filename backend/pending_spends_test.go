@@ -0,0 +1,66 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingSpends(t *testing.T) {
+	blockTime := int64(1000)
+	syncedHeight := int64(100)
+	spends := newPendingSpends(func() int64 { return syncedHeight }, func() int64 { return blockTime })
+
+	key := walletKey{token: gethcmn.Address{1}, payer: gethcmn.Address{2}}
+	other := walletKey{token: gethcmn.Address{1}, payer: gethcmn.Address{3}}
+	balance := uint256.NewInt(100)
+
+	s1, reserved, ok := spends.reserve(key, balance, uint256.NewInt(60), 2000, false)
+	require.True(t, ok)
+	require.True(t, reserved.IsZero())
+
+	// the second payment would double-spend the wallet
+	_, reserved, ok = spends.reserve(key, balance, uint256.NewInt(50), 2000, false)
+	require.False(t, ok)
+	require.EqualValues(t, 60, reserved.Uint64())
+	_, _, ok = spends.reserve(other, balance, uint256.NewInt(50), 2000, false)
+	require.True(t, ok)
+
+	// nothing is held in a dry run
+	s, _, ok := spends.reserve(key, balance, uint256.NewInt(40), 2000, true)
+	require.True(t, ok)
+	require.Nil(t, s)
+	require.EqualValues(t, 60, spends.reservedOf(key).Uint64())
+
+	// released on failure
+	spends.release(s1)
+	require.True(t, spends.reservedOf(key).IsZero())
+
+	// held until the followed state includes the settlement
+	s2, _, ok := spends.reserve(key, balance, uint256.NewInt(30), 2000, false)
+	require.True(t, ok)
+	spends.settle(s2, 101)
+	blockTime = 3000
+	require.EqualValues(t, 30, spends.reservedOf(key).Uint64())
+	syncedHeight = 101
+	require.True(t, spends.reservedOf(key).IsZero())
+
+	// released after the due time if it is not settled
+	_, _, ok = spends.reserve(key, balance, uint256.NewInt(30), 3500, false)
+	require.True(t, ok)
+	require.EqualValues(t, 30, spends.reservedOf(key).Uint64())
+	blockTime = 3501
+	require.True(t, spends.reservedOf(key).IsZero())
+	require.Empty(t, spends.wallets[key])
+
+	// the block time lags the wall clock, the payment due in the past can still be settled
+	blockTime = time.Now().Unix() - 60
+	_, _, ok = spends.reserve(key, balance, uint256.NewInt(30), time.Now().Unix()-30, false)
+	require.True(t, ok)
+	require.EqualValues(t, 30, spends.reservedOf(key).Uint64())
+	blockTime += 31
+	require.True(t, spends.reservedOf(key).IsZero())
+}
@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"time"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+
+	"github.com/smartbch/ganychain/app"
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+// QuotePrice returns the minimum expected value of the payment in the token for the bulletin,
+// according to the pricing policy of the shard which owns its topic.
+func (backend *Backend) QuotePrice(b *pb.Bulletin, token gethcmn.Address) (*app.PriceQuote, *app.AcceptedToken, error) {
+	acceptedToken, err := backend.appOf(b).GetAcceptedToken(token)
+	if err != nil {
+		return nil, nil, NewTokenNotAcceptedError(token)
+	}
+	return acceptedToken.Pricing.Quote(b), acceptedToken, nil
+}
+
+// GetAcceptedTokens returns SBCH and the whitelisted SEP20 tokens, which are the same on all the shards
+func (backend *Backend) GetAcceptedTokens() []*app.AcceptedToken {
+	return backend.apps[0].GetAcceptedTokens()
+}
+
+func (backend *Backend) checkPayment(sp *pb.StochasticPayment, b *pb.Bulletin) error {
+	token := app.TokenOf(sp)
+	acceptedToken, err := backend.appOf(b).GetAcceptedToken(token)
+	if err != nil {
+		return NewTokenNotAcceptedError(token)
+	}
+	policy := acceptedToken.Pricing
+	if policy.IsFree() {
+		return nil
+	}
+
+	ev, err := app.ExpectedValueOf(sp)
+	if err != nil {
+		return NewInvalidTxError(err)
+	}
+	quote := policy.Quote(b)
+	if ev.Lt(quote.MinPrice) {
+		return NewUnderpricedError(ev, quote.MinPrice)
+	}
+	return nil
+}
+
+// checkDueTime rejects the payments which would be due before they are settled,
+// with the same window as CheckTx of the shard
+func (backend *Backend) checkDueTime(sp *pb.StochasticPayment, b *pb.Bulletin) error {
+	blockTime := backend.follower.GetLatestBlockTime()
+	if blockTime == 0 {
+		blockTime = time.Now().Unix()
+	}
+	window := backend.appOf(b).GetMinDueTimeWindow()
+	if err := app.CheckDueTime(sp, blockTime, window); err != nil {
+		return NewPaymentDueError(sp.GetDueTime(), blockTime, window)
+	}
+	return nil
+}
+
+// appOf returns the shard which owns the topic of the bulletin
+func (backend *Backend) appOf(b *pb.Bulletin) app.GanyApp {
+	topicHash := b.GetTopicHash()
+	return backend.apps[backend.shardMap.ShardOf(topicHash[:])]
+}
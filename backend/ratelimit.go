@@ -0,0 +1,153 @@
+package backend
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+// the full buckets are dropped once the limiter tracks more keys than this
+const maxRateLimitBuckets = 100000
+
+// The keys of the rate limits
+const (
+	RateLimitKeyFrom   = "from"
+	RateLimitKeySigner = "signer"
+	RateLimitKeyIP     = "ip"
+)
+
+// RateLimit is a token bucket, which is refilled with `Rate` tokens per second and holds at most `Burst` tokens.
+type RateLimit struct {
+	Rate  float64 `mapstructure:"rate"` // zero means unlimited
+	Burst int     `mapstructure:"burst"`
+}
+
+func (l RateLimit) IsUnlimited() bool {
+	return l.Rate <= 0
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+}
+
+type rateLimiter struct {
+	mtx     sync.Mutex
+	config  *Config
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+func newRateLimiter(config *Config) *rateLimiter {
+	return &rateLimiter{
+		config:  config,
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// rateLimitRequest asks for a token from the bucket of (kind, id) under the limit
+type rateLimitRequest struct {
+	kind  string
+	id    string
+	limit RateLimit
+	typ   *pb.Bulletin_BulletinType // set for the type specific limits
+}
+
+// key is kind/id, and kind/type/id for the type specific limits
+func (r rateLimitRequest) key() string {
+	if r.typ != nil {
+		return r.kind + "/" + r.typ.String() + "/" + r.id
+	}
+	return r.kind + "/" + r.id
+}
+
+// allow takes a token from the bucket of (kind, id) under the limit, it returns a rate limited error if the bucket is empty.
+func (l *rateLimiter) allow(kind, id string, limit RateLimit, typ *pb.Bulletin_BulletinType) error {
+	return l.allowAll(rateLimitRequest{kind: kind, id: id, limit: limit, typ: typ})
+}
+
+// allowAll takes a token from each of the buckets only if none of them is empty,
+// otherwise it returns the rate limited error of the first empty one and takes nothing.
+func (l *rateLimiter) allowAll(requests ...rateLimitRequest) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := l.now()
+	buckets := make([]*tokenBucket, 0, len(requests))
+	for _, r := range requests {
+		if r.limit.IsUnlimited() {
+			continue
+		}
+		key := r.key()
+		bucket, ok := l.buckets[key]
+		if !ok {
+			if len(l.buckets) >= maxRateLimitBuckets {
+				l.dropFullBuckets(now)
+			}
+			bucket = &tokenBucket{tokens: float64(r.limit.Burst), last: now}
+			l.buckets[key] = bucket
+		}
+
+		bucket.limit = r.limit
+		bucket.refill(now)
+		if bucket.tokens < 1 {
+			retryAfter := time.Duration((1 - bucket.tokens) / r.limit.Rate * float64(time.Second))
+			return NewRateLimitedError(r.kind, r.id, retryAfter)
+		}
+		buckets = append(buckets, bucket)
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return nil
+}
+
+func (l *rateLimiter) allowAddress(kind string, addr gethcmn.Address, typ pb.Bulletin_BulletinType) error {
+	return l.allowAll(l.addressRequest(kind, addr, typ))
+}
+
+// addressRequest applies the limit of the bulletin type if there is one, or the address limit shared by all the types
+func (l *rateLimiter) addressRequest(kind string, addr gethcmn.Address, typ pb.Bulletin_BulletinType) rateLimitRequest {
+	if limit, ok := l.config.TypeRateLimits[strings.ToLower(typ.String())]; ok {
+		return rateLimitRequest{kind: kind, id: addr.Hex(), limit: limit, typ: &typ}
+	}
+	return rateLimitRequest{kind: kind, id: addr.Hex(), limit: l.config.AddressRateLimit}
+}
+
+func (l *rateLimiter) dropFullBuckets(now time.Time) {
+	for key, bucket := range l.buckets {
+		// a full bucket is the same as a new one
+		if bucket.refill(now); bucket.tokens >= float64(bucket.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// ----------------------------------------------------------------
+
+// CheckIPRateLimit is called by the RPC layer before the tx is handled
+func (backend *Backend) CheckIPRateLimit(ip string) error {
+	return backend.limiter.allow(RateLimitKeyIP, ip, backend.config.IPRateLimit, nil)
+}
+
+// checkAddressRateLimits charges the buckets of Bulletin.From and the signer, none of them is charged if either is empty.
+// It must be called after the signature and the delegation are checked, otherwise anyone could drain the bucket of
+// a victim with unsigned txs, the IP limit covers the txs failing before it.
+func (backend *Backend) checkAddressRateLimits(b *pb.Bulletin, signer gethcmn.Address) error {
+	return backend.limiter.allowAll(
+		backend.limiter.addressRequest(RateLimitKeyFrom, gethcmn.BytesToAddress(b.From), b.GetType()),
+		backend.limiter.addressRequest(RateLimitKeySigner, signer, b.GetType()),
+	)
+}
@@ -0,0 +1,59 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	pb "github.com/smartbch/ganychain/proto"
+)
+
+func TestRateLimiter(t *testing.T) {
+	config := DefaultConfig()
+	config.AddressRateLimit = RateLimit{Rate: 1, Burst: 2}
+	config.TypeRateLimits = map[string]RateLimit{"comment": {Rate: 0.5, Burst: 1}}
+	config.IPRateLimit = RateLimit{}
+
+	now := time.Unix(1000, 0)
+	limiter := newRateLimiter(config)
+	limiter.now = func() time.Time { return now }
+
+	addr := gethcmn.HexToAddress("0x06C14ED469FB93545cbF071b593D8f90194Ede62")
+	require.NoError(t, limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_BLOG))
+	require.NoError(t, limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_BLOG))
+	err := limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_BLOG)
+	require.Equal(t, ErrCodeRateLimited, ErrorCodeOf(err))
+	require.EqualValues(t, 1000, err.(*Error).Data.(RateLimitedData).RetryAfter)
+
+	// the signer has its own bucket
+	require.NoError(t, limiter.allowAddress(RateLimitKeySigner, addr, pb.Bulletin_BLOG))
+
+	// the comments are limited separately
+	require.NoError(t, limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_COMMENT))
+	require.Error(t, limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_COMMENT))
+
+	now = now.Add(time.Second)
+	require.NoError(t, limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_BLOG))
+	require.Error(t, limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_COMMENT))
+
+	now = now.Add(time.Second)
+	require.NoError(t, limiter.allowAddress(RateLimitKeyFrom, addr, pb.Bulletin_COMMENT))
+
+	// neither bucket is charged if the signer is limited
+	from := gethcmn.HexToAddress("0x423403784Ca5bD868731d604Ad097f126B36CAe2")
+	for i := 0; i < 2; i++ {
+		require.NoError(t, limiter.allowAddress(RateLimitKeySigner, addr, pb.Bulletin_BLOG))
+	}
+	err = limiter.allowAll(limiter.addressRequest(RateLimitKeyFrom, from, pb.Bulletin_BLOG),
+		limiter.addressRequest(RateLimitKeySigner, addr, pb.Bulletin_BLOG))
+	require.Equal(t, RateLimitKeySigner, err.(*Error).Data.(RateLimitedData).Key)
+	require.NoError(t, limiter.allowAddress(RateLimitKeyFrom, from, pb.Bulletin_BLOG))
+	require.NoError(t, limiter.allowAddress(RateLimitKeyFrom, from, pb.Bulletin_BLOG))
+
+	// unlimited
+	for i := 0; i < 100; i++ {
+		require.NoError(t, limiter.allow(RateLimitKeyIP, "127.0.0.1", config.IPRateLimit, nil))
+	}
+}
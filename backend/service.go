@@ -1,11 +1,15 @@
 package backend
 
 import (
+	geth "github.com/ethereum/go-ethereum"
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 
+	"github.com/smartbch/ganychain/app"
+	"github.com/smartbch/ganychain/follower"
 	pb "github.com/smartbch/ganychain/proto"
+	"github.com/smartbch/ganychain/web3client"
 )
 
 type BackendService interface {
@@ -15,8 +19,30 @@ type BackendService interface {
 	QueryBulletinByTimePeriod(typ pb.Bulletin_BulletinType, topicHash [32]byte, start, end int64,
 		excludeSNs map[string]struct{}) ([]*pb.Bulletin, error)
 	PutBulletin(tx pb.GanyTx) (tmbytes.HexBytes, error)
+	SimulateBulletin(tx pb.GanyTx) *SimulationReport
+	QuotePrice(b *pb.Bulletin, token gethcmn.Address) (*app.PriceQuote, *app.AcceptedToken, error)
+	GetAcceptedTokens() []*app.AcceptedToken
+	CheckIPRateLimit(ip string) error
+
+	// fan-out queries across all the shards
+	QueryBulletinsByAuthor(from []byte, start, end int64) (*FanOutResult, error)
+	QueryBulletinsByTime(types []pb.Bulletin_BulletinType, start, end int64) (*FanOutResult, error)
+	SearchBulletins(keyword []byte, start, end int64) (*FanOutResult, error)
 
 	GetDelegatedAddr(mainAddress gethcmn.Address) (gethcmn.Address, error)
 	LoadWalletInStochasticPay(tokenAddr, ownerAddr gethcmn.Address) (*uint256.Int, *uint256.Int, error)
+	GetWalletEvents(owner, token gethcmn.Address, startHeight, endHeight int64) ([]*follower.WalletEvent, error)
 	GetValidatorPubKeyList() [][]byte
+	GetValidators() []*follower.ValidatorInfo
+	GetValidatorSetAt(height int64) (*follower.ValidatorSet, error)
+	GetValidatorSetByRoot(root []byte) (*follower.ValidatorSet, error)
+	GetValidatorSetByElectedTime(electedTime int64) (*follower.ValidatorSet, error)
+	GetSyncStatus() follower.SyncStatus
+	EthCall(msg geth.CallMsg, height int64) (*follower.CallResult, error)
+	GetBulletinCacheStats() BulletinCacheStats
+	GetUpstreamStats() []web3client.EndpointStats
+
+	// authenticator
+	GetAuthenticatorAddress() (gethcmn.Address, bool)
+	SignDynamicSetProof(chainId *uint256.Int, from, targetContract gethcmn.Address, functionSelector uint32, maxTimeDifference int64) (*pb.DynamicSetProof, error)
 }
@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartbch/ganychain/web3client"
+)
+
+type receiptClient struct {
+	web3client.Web3Client
+	receipt *gethtypes.Receipt // nil if the tx is not mined yet
+}
+
+func (c *receiptClient) TransactionReceipt(ctx context.Context, txHash gethcmn.Hash) (*gethtypes.Receipt, error) {
+	if c.receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return c.receipt, nil
+}
+
+func TestAwaitSettlement(t *testing.T) {
+	client := &receiptClient{}
+	backend := &Backend{sbchClient: client, pendingSpends: newPendingSpends(func() int64 { return 100 }, func() int64 { return 0 })}
+	key := walletKey{token: gethcmn.Address{1}, payer: gethcmn.Address{2}}
+	reserve := func() *pendingSpend {
+		spend, _, ok := backend.pendingSpends.reserve(key, uint256.NewInt(100), uint256.NewInt(10), time.Now().Add(time.Hour).Unix(), false)
+		require.True(t, ok)
+		return spend
+	}
+
+	// the outcome is unknown, the reservation is kept
+	err := backend.awaitSettlement(reserve(), gethcmn.Hash{}, 10*time.Millisecond)
+	require.Equal(t, ErrCodeSettlementFailed, err.(*Error).Code)
+	require.EqualValues(t, 10, backend.pendingSpends.reservedOf(key).Uint64())
+
+	// reverted, the wallet is not charged
+	client.receipt = &gethtypes.Receipt{Status: gethtypes.ReceiptStatusFailed, BlockNumber: big.NewInt(101)}
+	err = backend.awaitSettlement(reserve(), gethcmn.Hash{}, time.Second)
+	require.Equal(t, ErrCodeSettlementFailed, err.(*Error).Code)
+	require.EqualValues(t, 10, backend.pendingSpends.reservedOf(key).Uint64())
+
+	// settled, kept until the followed state includes it
+	client.receipt = &gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful, BlockNumber: big.NewInt(101)}
+	require.NoError(t, backend.awaitSettlement(reserve(), gethcmn.Hash{}, time.Second))
+	require.EqualValues(t, 20, backend.pendingSpends.reservedOf(key).Uint64())
+}
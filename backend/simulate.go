@@ -0,0 +1,176 @@
+package backend
+
+import (
+	"fmt"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+
+	"github.com/smartbch/ganychain/app"
+	"github.com/smartbch/ganychain/contract"
+	pb "github.com/smartbch/ganychain/proto"
+	"github.com/smartbch/ganychain/utils/ethutils"
+)
+
+// The steps of PutBulletin, in the order they are checked
+const (
+	StepParse         = "parse"
+	StepPaymentMode   = "payment-mode"
+	StepValidatorRoot = "validator-root"
+	StepDueTime       = "due-time"
+	StepPricing       = "pricing"
+	StepRecoverSigner = "recover-signer"
+	StepDelegation    = "delegation"
+	StepNonces        = "nonces-and-balance"
+	StepAuth          = "auth-proof"
+	StepProbability   = "probability"
+	StepShard         = "shard"
+)
+
+type SimulationStep struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Skipped bool   `json:"skipped,omitempty"` // a step it depends on failed
+	Detail  string `json:"detail,omitempty"`
+	Code    int    `json:"code,omitempty"` // the JSON-RPC error code PutBulletin would return
+	Error   string `json:"error,omitempty"`
+}
+
+// SimulationReport is the result of a dry run of PutBulletin
+type SimulationReport struct {
+	Passed  bool             `json:"passed"`
+	Signer  *gethcmn.Address `json:"signer,omitempty"`
+	Shard   uint32           `json:"shard"`
+	ChainId string           `json:"chainId"`
+	Steps   []SimulationStep `json:"steps"`
+}
+
+func (r *SimulationReport) addStep(name, detail string, err error) {
+	step := SimulationStep{Name: name, Passed: err == nil, Detail: detail}
+	if err != nil {
+		step.Code = ErrorCodeOf(err)
+		step.Error = err.Error()
+		r.Passed = false
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+// Step returns the step named `name`, or nil if the report has no such step
+func (r *SimulationReport) Step(name string) *SimulationStep {
+	for i := range r.Steps {
+		if r.Steps[i].Name == name {
+			return &r.Steps[i]
+		}
+	}
+	return nil
+}
+
+func (r *SimulationReport) skipSteps(names ...string) {
+	for _, name := range names {
+		r.Steps = append(r.Steps, SimulationStep{Name: name, Skipped: true})
+	}
+	r.Passed = false
+}
+
+// SimulateBulletin runs all the checks of PutBulletin, without broadcasting the tx or calling payToAB.
+// The independent checks still run after a failed one, so the report shows every problem of the tx.
+func (backend *Backend) SimulateBulletin(tx pb.GanyTx) *SimulationReport {
+	report := &SimulationReport{Passed: true}
+
+	// 1. parse the tx
+	sp, b, ap, err := parseGanyTx(tx)
+	report.addStep(StepParse, "", err)
+	if err != nil {
+		report.skipSteps(StepPaymentMode, StepValidatorRoot, StepDueTime, StepPricing, StepRecoverSigner, StepDelegation, StepNonces, StepAuth, StepProbability, StepShard)
+		return report
+	}
+
+	// 2. check the payment mode
+	mode := "ab"
+	if sp.IsSingleReceiver() {
+		mode = "sr"
+	}
+	report.addStep(StepPaymentMode, mode, backend.checkSingleReceiver(sp))
+
+	// 3. check the validator set which the payment is signed for
+	if sp.IsSingleReceiver() {
+		report.addStep(StepValidatorRoot, "not required by sr", nil)
+	} else {
+		_, err = backend.validatorTreeOf(sp.ValidatorPubkeyHashRoot)
+		report.addStep(StepValidatorRoot, "", err)
+	}
+
+	// 4. check the due time
+	report.addStep(StepDueTime, fmt.Sprintf("dueTime: %v", sp.GetDueTime()), backend.checkDueTime(sp, b))
+
+	// 5. check the price
+	detail := ""
+	if quote, token, err := backend.QuotePrice(b, app.TokenOf(sp)); err == nil {
+		detail = fmt.Sprintf("minPrice: %v %v", quote.MinPrice.ToBig(), token.Symbol)
+	}
+	report.addStep(StepPricing, detail, backend.checkPayment(sp, b))
+
+	// 6. recover the signer
+	msg, eip712Types := genEIP712Msg(sp)
+	typedData := ethutils.GetStochasticPayTypedData(eip712Types, msg, contract.StochasticPayVRFAddress)
+	eip712Hash, err := ethutils.GetTypedDataHash(typedData)
+	if err == nil {
+		report.Signer, _, err = ethutils.EcRecover(eip712Hash, sp.Signature)
+	}
+	if err != nil {
+		err = NewBadSignatureError(err.Error(), nil)
+	}
+	if err != nil {
+		report.addStep(StepRecoverSigner, "", err)
+		report.skipSteps(StepDelegation, StepNonces)
+	} else {
+		report.addStep(StepRecoverSigner, report.Signer.Hex(), nil)
+
+		// 7. check the delegation
+		err = backend.checkDelegatedAddr(b, *report.Signer)
+		report.addStep(StepDelegation, "", err)
+
+		// 8. check nonces and the balance minus the pending spends
+		_, err = backend.checkNoncesAndBalance(sp, *report.Signer, false)
+		report.addStep(StepNonces, "", err)
+	}
+
+	// 9. check auth
+	err = backend.checkAuth(sp, b, ap)
+	report.addStep(StepAuth, "", err)
+
+	// 10. preview the VRF outcome
+	rand32, _, err := genRand32(validatorPrivateKey, msg)
+	if err == nil && sp.Probability < rand32 {
+		err = NewVRFLostError(sp.Probability, rand32)
+	}
+	report.addStep(StepProbability, fmt.Sprintf("prob32: %v, rand32: %v", sp.Probability, rand32), err)
+
+	// 11. select the shard
+	topicHash := b.GetTopicHash()
+	report.Shard = backend.shardMap.ShardOf(topicHash[:])
+	report.ChainId = backend.apps[report.Shard].GetChainId()
+	report.addStep(StepShard, fmt.Sprintf("shard: %v, chainId: %v", report.Shard, report.ChainId), nil)
+	return report
+}
+
+func parseGanyTx(tx pb.GanyTx) (*pb.StochasticPayment, *pb.Bulletin, *pb.AuthProof, error) {
+	if isValid, err := tx.IsValid(); !isValid {
+		return nil, nil, nil, NewInvalidTxError(err)
+	}
+
+	sp, err := tx.GetStochasticPayment()
+	if err != nil {
+		return nil, nil, nil, NewInvalidTxError(err)
+	}
+
+	b, err := tx.GetBulletin()
+	if err != nil {
+		return nil, nil, nil, NewInvalidTxError(err)
+	}
+
+	ap, err := tx.GetAuthProof()
+	if err != nil {
+		return nil, nil, nil, NewInvalidTxError(err)
+	}
+	return sp, b, ap, nil
+}
@@ -0,0 +1,172 @@
+package backend_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartbch/ganychain/app"
+	"github.com/smartbch/ganychain/backend"
+	"github.com/smartbch/ganychain/contract"
+	pb "github.com/smartbch/ganychain/proto"
+	"github.com/smartbch/ganychain/utils/ethutils"
+	"github.com/smartbch/ganychain/utils/testutils"
+)
+
+func TestSimulateBulletin(t *testing.T) {
+	apps := make([]app.GanyApp, NumOfShards)
+	dbs := make([]*badger.DB, NumOfShards)
+
+	for i := 0; i < NumOfShards; i++ {
+		dbPath := fmt.Sprintf(TestDataDirTemplate, i)
+		db, err := badger.Open(badger.DefaultOptions(dbPath))
+		require.NoError(t, err)
+
+		dbs[i] = db
+		apps[i] = testutils.CreateMockGanyApp(db)
+	}
+
+	defer cleanData(dbs)
+
+	validatorCompressedPubKeys := getCompressedValidatorPubKeys()
+	mockFollower := testutils.NewMockFollower(map[gethcmn.Address]gethcmn.Address{main: payer}, validatorCompressedPubKeys)
+	mockBackend := testutils.NewMockBackend(apps, mockFollower, testutils.NewMockClient(), token, contractAddr, validator, validatorPrivateKey)
+	mockFollower.SetWallet(payer, uint256.NewInt(0), uint256.NewInt(100))
+
+	tree, _ := genMerkleTree(validatorCompressedPubKeys)
+	now := time.Now()
+	genTx := func(root, nonces []byte) pb.GanyTx {
+		sp := &pb.StochasticPayment{
+			ValidatorPubkeyHashRoot: root,
+			DueTime:                 now.Add(time.Hour).Unix(),
+			Probability:             Prob32,
+			Nonces:                  nonces,
+			AmountToPayee:           gethcmn.FromHex("0x00"),
+			AmountToValidator:       amountToValidator,
+			Signature:               make([]byte, 65),
+		}
+
+		// signed for the real contracts, which are used by the backend
+		msg := sp.GenEIP712MsgForAB(contract.SBCHTokenAddress)
+		typedData := ethutils.GetStochasticPayTypedData(ethutils.EIP712TypesForAB, msg, contract.StochasticPayVRFAddress)
+		eip712Hash, err := ethutils.GetTypedDataHash(typedData)
+		require.NoError(t, err)
+
+		sig, err := ethutils.SignWithEIP712Hash(eip712Hash, payerPrivateKey)
+		require.NoError(t, err)
+		copy(sp.Signature[:], sig)
+
+		b := &pb.Bulletin{
+			Type:        pb.Bulletin_BLOG,
+			Topic:       []byte{0x12},
+			Timestamp:   now.Unix(),
+			Duration:    now.Add(time.Hour).Unix(),
+			From:        main.Bytes(),
+			ContentType: "My Blog",
+			ContentList: [][]byte{
+				{1, 2},
+			},
+		}
+		return pb.CreateGanyTx(sp, b, nil, nil)
+	}
+
+	noncesBz := uint256.NewInt(0).Bytes32()
+	report := mockBackend.SimulateBulletin(genTx(tree.MerkleRoot(), noncesBz[:]))
+	require.True(t, report.Passed, "%+v", report.Steps)
+	require.Equal(t, payer, *report.Signer)
+	require.Equal(t, apps[report.Shard].GetChainId(), report.ChainId)
+	require.Len(t, report.Steps, 11)
+
+	// the wrong nonces do not stop the other checks
+	wrongNoncesBz := uint256.NewInt(1).Bytes32()
+	report = mockBackend.SimulateBulletin(genTx(tree.MerkleRoot(), wrongNoncesBz[:]))
+	require.False(t, report.Passed)
+	for _, step := range report.Steps {
+		require.False(t, step.Skipped)
+		require.Equal(t, step.Name != backend.StepNonces, step.Passed, step.Name)
+		if step.Name == backend.StepNonces {
+			require.Equal(t, backend.ErrCodeInvalidTx, step.Code)
+		}
+	}
+
+	// the previous validator set is accepted within the grace period after an election
+	prevTree, _ := genMerkleTree(validatorCompressedPubKeys[:2])
+	mockFollower.SetPreviousValidatorPubKeys(validatorCompressedPubKeys[:2], now.Add(-time.Minute).Unix())
+	report = mockBackend.SimulateBulletin(genTx(prevTree.MerkleRoot(), noncesBz[:]))
+	require.True(t, report.Passed, "%+v", report.Steps)
+
+	mockFollower.SetPreviousValidatorPubKeys(validatorCompressedPubKeys[:2], now.Add(-time.Hour).Unix())
+	report = mockBackend.SimulateBulletin(genTx(prevTree.MerkleRoot(), noncesBz[:]))
+	require.False(t, report.Passed)
+	require.Equal(t, backend.ErrCodeStaleValidatorRoot, report.Step(backend.StepValidatorRoot).Code)
+
+	// the payment would be due before it is settled
+	mockFollower.SetBlockTime(now.Add(time.Hour - time.Second).Unix())
+	report = mockBackend.SimulateBulletin(genTx(tree.MerkleRoot(), noncesBz[:]))
+	require.False(t, report.Passed)
+	require.Equal(t, backend.ErrCodePaymentDue, report.Step(backend.StepDueTime).Code)
+	mockFollower.SetBlockTime(now.Unix())
+
+	// the balance cannot be checked while the follower lags behind
+	mockFollower.SetSyncStatus(100, 200)
+	report = mockBackend.SimulateBulletin(genTx(tree.MerkleRoot(), noncesBz[:]))
+	require.False(t, report.Passed)
+	require.Equal(t, backend.ErrCodeFollowerLagging, report.Step(backend.StepNonces).Code)
+	require.Equal(t, int64(100), mockBackend.GetSyncStatus().Lag)
+	mockFollower.SetSyncStatus(200, 200)
+
+	// nothing can be checked without parsing the tx
+	report = mockBackend.SimulateBulletin(pb.GanyTx{0x01})
+	require.False(t, report.Passed)
+	require.False(t, report.Step(backend.StepParse).Passed)
+	require.Equal(t, backend.ErrCodeInvalidTx, report.Step(backend.StepParse).Code)
+	for _, step := range report.Steps {
+		require.True(t, step.Name == backend.StepParse || step.Skipped, step.Name)
+	}
+
+	// a single receiver payment to the validator
+	sp := &pb.StochasticPayment{
+		ValidatorPubkey: gethcrypto.CompressPubkey(&validatorPrivateKey.PublicKey),
+		DueTime:         now.Add(time.Hour).Unix(),
+		Probability:     Prob32,
+		Nonces:          noncesBz[:],
+		Payee:           validator.Bytes(),
+		AmountToPayee:   amountToValidator,
+		Signature:       make([]byte, 65),
+	}
+	msg := sp.GenEIP712MsgForSR(contract.SBCHTokenAddress)
+	typedData := ethutils.GetStochasticPayTypedData(ethutils.EIP712TypesForSR, msg, contract.StochasticPayVRFAddress)
+	eip712Hash, err := ethutils.GetTypedDataHash(typedData)
+	require.NoError(t, err)
+	sig, err := ethutils.SignWithEIP712Hash(eip712Hash, payerPrivateKey)
+	require.NoError(t, err)
+	copy(sp.Signature[:], sig)
+
+	b := &pb.Bulletin{
+		Type:        pb.Bulletin_BLOG,
+		Topic:       []byte{0x12},
+		Timestamp:   now.Unix(),
+		Duration:    now.Add(time.Hour).Unix(),
+		From:        main.Bytes(),
+		ContentType: "My Blog",
+		ContentList: [][]byte{
+			{1, 2},
+		},
+	}
+	report = mockBackend.SimulateBulletin(pb.CreateGanyTx(sp, b, nil, nil))
+	require.True(t, report.Passed, "%+v", report.Steps)
+	require.Equal(t, payer, *report.Signer)
+	require.Equal(t, "sr", report.Step(backend.StepPaymentMode).Detail)
+
+	// the single receiver must be the validator
+	sp.Payee = payee.Bytes()
+	report = mockBackend.SimulateBulletin(pb.CreateGanyTx(sp, b, nil, nil))
+	require.False(t, report.Passed)
+	require.False(t, report.Step(backend.StepPaymentMode).Passed)
+}
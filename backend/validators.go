@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/smartbch/merkletree"
+
+	"github.com/smartbch/ganychain/utils/cryptoutils"
+)
+
+// validatorTreeOf returns the Merkle tree of the validator set which the payment is signed for:
+// the elected set, or the previous one within the grace period after an election.
+func (backend *Backend) validatorTreeOf(root []byte) (*merkletree.MerkleTree, error) {
+	tree, err := cryptoutils.NewValidatorPubKeysMerkleTree(backend.follower.GetValidatorPubKeyList())
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(tree.MerkleRoot(), root) {
+		return tree, nil
+	}
+
+	prevPubKeys, electedTime := backend.follower.GetPreviousValidatorPubKeyList()
+	inGracePeriod := time.Since(time.Unix(electedTime, 0)) <= backend.config.ValidatorRootGracePeriod
+	if len(prevPubKeys) != 0 && inGracePeriod {
+		prevTree, err := cryptoutils.NewValidatorPubKeysMerkleTree(prevPubKeys)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(prevTree.MerkleRoot(), root) {
+			return prevTree, nil
+		}
+	}
+	return nil, NewStaleValidatorRootError(root, tree.MerkleRoot())
+}
@@ -44,8 +44,14 @@ var (
 	flagRpcHttpAddr  string
 	flagRpcHttpsAddr string
 	flagRpcHttpApi   string
-	flagSbchRpcAddr  string
-	flagSbchWsAddr   string
+	flagSbchRpcAddrs []string
+	flagSbchWsAddrs  []string
+
+	// app config
+	appConfig *app.Config
+
+	// backend config
+	backendConfig *backend.Config
 
 	// tendermint node config
 	numOfShards int // number of shards
@@ -53,6 +59,9 @@ var (
 
 	// socket server config
 	serverPorts []string
+
+	// reshard config
+	flagNewShards int
 )
 
 var RootCmd = &cobra.Command{
@@ -92,8 +101,37 @@ func readConfig() {
 	flagRpcHttpsAddr = viper.GetString("rpc.https-addr")
 	flagRpcHttpApi = viper.GetString("rpc.http-api")
 
-	flagSbchRpcAddr = viper.GetString("follower.smartbch-rpc-url")
-	flagSbchWsAddr = viper.GetString("follower.smartbch-ws-url")
+	flagSbchRpcAddrs = endpointsOf(viper.GetString("follower.smartbch-rpc-url"),
+		viper.GetStringSlice("follower.smartbch-rpc-urls"))
+	flagSbchWsAddrs = endpointsOf(viper.GetString("follower.smartbch-ws-url"),
+		viper.GetStringSlice("follower.smartbch-ws-urls"))
+
+	appConfig = app.DefaultConfig()
+	err = viper.UnmarshalKey("app", appConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read app config: %v\n", err)
+		os.Exit(1)
+	}
+
+	backendConfig = backend.DefaultConfig()
+	err = viper.UnmarshalKey("backend", backendConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read backend config: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// endpointsOf returns the endpoint `url` followed by the others in `urls`, without the duplicated ones
+func endpointsOf(url string, urls []string) []string {
+	var endpoints []string
+	seen := make(map[string]bool)
+	for _, u := range append([]string{url}, urls...) {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			endpoints = append(endpoints, u)
+		}
+	}
+	return endpoints
 }
 
 func addGlobalFlags() {
@@ -110,6 +148,9 @@ func addGlobalFlags() {
 func addCommands() {
 	RootCmd.AddCommand(startCmd)
 	RootCmd.AddCommand(followerCmd)
+	RootCmd.AddCommand(reshardCmd)
+
+	reshardCmd.Flags().IntVarP(&flagNewShards, "shards", "", 0, "the new number of shards")
 }
 
 var startCmd = &cobra.Command{
@@ -128,6 +169,14 @@ var followerCmd = &cobra.Command{
 	RunE:  cmdInitFollower,
 }
 
+var reshardCmd = &cobra.Command{
+	Use:   "reshard",
+	Short: "move topics between shards",
+	Long:  "move topics between shard databases according to a new shard map, the node must be stopped. If it is interrupted, run it again with the same --shards to finish it",
+	Args:  cobra.ExactArgs(0),
+	RunE:  cmdReshard,
+}
+
 //--------------------------------------------------------------------------------
 
 func cmdInitFollower(cmd *cobra.Command, args []string) error {
@@ -151,12 +200,17 @@ func ensureRoot(home string) {
 func cmdGanyApp(cmd *cobra.Command, args []string) error {
 	logger := tmlog.MustNewDefaultLogger(tmlog.LogFormatPlain, tmlog.LogLevelInfo, false)
 
+	followerConfig := follower.DefaultConfig(flagFollowerHome, flagSbchRpcAddrs, flagSbchWsAddrs)
+	sbchClient, err := web3client.NewMultiClient(followerConfig.SmartBchRPCUrls, logger.With("module", "web3client"))
+	if err != nil {
+		return err
+	}
+
 	apps := make([]app.GanyApp, numOfShards)
 	dbs := make([]*badger.DB, numOfShards)
 	ctx, cancel := context.WithCancel(context.Background())
 
-	sbchClient := web3client.NewSbchClient(flagSbchRpcAddr)
-	followerConfig := follower.DefaultConfig(flagFollowerHome, flagSbchRpcAddr, flagSbchWsAddr)
+	go sbchClient.Run(ctx)
 	follower := follower.NewSbchFollower(followerConfig, logger, sbchClient)
 
 	for i, tmPort := range shardPorts {
@@ -168,7 +222,7 @@ func cmdGanyApp(cmd *cobra.Command, args []string) error {
 		}
 
 		dbs[i] = db
-		apps[i] = app.NewGanyApplication(db, tmPort, logger.With("module", "gany-app", "shard", i))
+		apps[i] = app.NewGanyApplication(db, tmPort, appConfig, follower, logger.With("module", "gany-app", "shard", i))
 		go startNewListener(ctx, apps[i], serverPorts[i], flagAbci, logger.With("module", "abci-server", "shard", i))
 		go runBadgerGC(db, logger.With("module", "badger-db", "shard", i))
 	}
@@ -188,6 +242,62 @@ func cmdGanyApp(cmd *cobra.Command, args []string) error {
 	select {}
 }
 
+func cmdReshard(cmd *cobra.Command, args []string) error {
+	logger := tmlog.MustNewDefaultLogger(tmlog.LogFormatPlain, tmlog.LogLevelInfo, false)
+
+	if flagNewShards <= 0 {
+		return fmt.Errorf("invalid number of shards: %d", flagNewShards)
+	}
+
+	numOfDbs := numOfShards
+	if flagNewShards > numOfDbs {
+		numOfDbs = flagNewShards
+	}
+
+	dbs := make([]*badger.DB, numOfDbs)
+	for i := 0; i < numOfDbs; i++ {
+		db, err := badger.Open(badger.DefaultOptions(fmt.Sprintf(DBPathTemplate, i)))
+		if err != nil {
+			closeDbs(dbs[:i])
+			return fmt.Errorf("failed to open badger db: %w", err)
+		}
+		dbs[i] = db
+	}
+	defer closeDbs(dbs)
+
+	current := app.NewLegacyShardMap(uint32(numOfShards))
+	for i := 0; i < numOfShards; i++ {
+		m, err := app.NewGanyApplication(dbs[i], shardPorts[i], appConfig, nil, logger).GetShardMap()
+		if err != nil {
+			return err
+		}
+		if m != nil && m.Version > current.Version {
+			current = m
+		}
+	}
+
+	next := current.Resize(uint32(flagNewShards))
+	if current.NumOfShards != uint32(numOfShards) {
+		// an interrupted reshard has recorded the new map in some shards, resume it
+		if current.NumOfShards != uint32(flagNewShards) {
+			return fmt.Errorf("number of shards (%d) mismatches shard map version %d (%d shards)",
+				numOfShards, current.Version, current.NumOfShards)
+		}
+		next = current
+	}
+	logger.Info("resharding", "fromVersion", current.Version, "toVersion", next.Version,
+		"fromShards", current.NumOfShards, "toShards", next.NumOfShards)
+
+	moved, err := app.MigrateShards(dbs, next, logger.With("module", "reshard"))
+	if err != nil {
+		return err
+	}
+
+	logger.Info("resharding done, please update tendermint.shards and the ports in config",
+		"moved", moved, "version", next.Version)
+	return nil
+}
+
 func closeDbs(dbs []*badger.DB) {
 	for _, d := range dbs {
 		d.Close()
@@ -225,7 +335,7 @@ func startRpcServer(ctx context.Context, apps []app.GanyApp, followerApp followe
 	rpcAddrSecure, wsAddrSecure, corsDomain, certFile, keyFile string, logger tmlog.Logger, httpAPI, wsAPI string) {
 
 	serverCfg := tmrpcserver.DefaultConfig()
-	rpcBackend := backend.NewBackend(apps, followerApp, sbchClient)
+	rpcBackend := backend.NewBackend(apps, followerApp, sbchClient, backendConfig, logger.With("module", "backend"))
 
 	rpcServer := rpc.NewServer(rpcAddr, wsAddr, rpcAddrSecure, wsAddrSecure, corsDomain, certFile, keyFile,
 		serverCfg, rpcBackend, logger, httpAPI, wsAPI)
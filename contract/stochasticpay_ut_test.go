@@ -131,18 +131,15 @@ func TestPayToSingleReceiver(t *testing.T) {
 	nonces, _ := uint256.FromBig(loadWalletRes2.Nonces)
 	noncesBz := nonces.Bytes32()
 
-	tree := genMerkleTree()
-
 	now := time.Now()
 	sp := &pb.StochasticPayment{
-		ValidatorPubkeyHashRoot: tree.MerkleRoot(),
-		DueTime:                 now.Add(time.Hour).Unix(),
-		Probability:             1000,
-		Nonces:                  noncesBz[:],
-		Payee:                   payee.Bytes(),
-		AmountToPayee:           gethcmn.FromHex("0x50"),
-		AmountToValidator:       gethcmn.FromHex("0x00"),
-		Signature:               make([]byte, 65),
+		ValidatorPubkey: gethcrypto.CompressPubkey(&payeePrivateKey.PublicKey),
+		DueTime:         now.Add(time.Hour).Unix(),
+		Probability:     1000,
+		Nonces:          noncesBz[:],
+		Payee:           payee.Bytes(),
+		AmountToPayee:   gethcmn.FromHex("0x50"),
+		Signature:       make([]byte, 65),
 	}
 
 	msg := sp.GenEIP712MsgForSR(tokenAddr)
@@ -0,0 +1,140 @@
+package follower
+
+import (
+	"errors"
+	"fmt"
+
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	"github.com/smartbch/moeingads/store/rabbit"
+	"github.com/smartbch/moeingevm/ebp"
+	moevmtypes "github.com/smartbch/moeingevm/types"
+)
+
+// LatestHeight runs a call on the latest followed block
+const LatestHeight = -1
+
+var ErrHistoricalStateUnavailable = errors.New("the state before the latest followed block is only kept in archive mode")
+
+// CallResult is the output of a read-only call, and the block it runs on
+type CallResult struct {
+	ReturnData []byte
+	GasUsed    uint64
+	Height     int64
+	BlockTime  int64
+}
+
+// EthCall runs a read-only call with moeingevm on the followed state at `height`, like eth_call.
+// The gas is capped by CallGasLimit, and a reverted call returns an error with the revert reason.
+func (app *SbchFollower) EthCall(msg geth.CallMsg, height int64) (*CallResult, error) {
+	tx, err := txToRunOf(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	app.stateMtx.RLock()
+	defer app.stateMtx.RUnlock()
+	ctx, bi, err := app.getRpcContextAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close(false)
+
+	tx.Height = uint64(bi.Number)
+	runner := ebp.NewTxRunner(ctx, tx)
+	runner.ForRpc = true // the nonce of the sender is not checked
+	ebp.RunTxForRpc(bi, false, runner)
+	if ebp.StatusIsFailure(runner.Status) {
+		if reason, err := abi.UnpackRevert(runner.OutData); err == nil {
+			return nil, fmt.Errorf("call failed: %s: %s", ebp.StatusToStr(runner.Status), reason)
+		}
+		return nil, fmt.Errorf("call failed: %s", ebp.StatusToStr(runner.Status))
+	}
+	return &CallResult{
+		ReturnData: runner.OutData,
+		GasUsed:    runner.GasUsed,
+		Height:     bi.Number,
+		BlockTime:  bi.Timestamp,
+	}, nil
+}
+
+func (app *SbchFollower) CallContract(from, to gethcmn.Address, data []byte) ([]byte, int64, error) {
+	result, err := app.EthCall(geth.CallMsg{From: from, To: &to, Data: data}, LatestHeight)
+	if err != nil {
+		return nil, 0, err
+	}
+	return result.ReturnData, result.BlockTime, nil
+}
+
+func txToRunOf(msg geth.CallMsg) (*moevmtypes.TxToRun, error) {
+	if msg.To == nil {
+		return nil, errors.New("contract creation is not supported in a call")
+	}
+	tx := &moevmtypes.TxToRun{
+		BasicTx: moevmtypes.BasicTx{
+			From: msg.From,
+			To:   *msg.To,
+			Gas:  msg.Gas,
+			Data: msg.Data,
+		},
+	}
+	if tx.Gas == 0 || tx.Gas > CallGasLimit {
+		tx.Gas = CallGasLimit
+	}
+	if msg.Value != nil {
+		value, overflow := uint256.FromBig(msg.Value)
+		if overflow || msg.Value.Sign() < 0 {
+			return nil, errors.New("invalid value")
+		}
+		tx.Value = value.Bytes32()
+	}
+	if msg.GasPrice != nil {
+		gasPrice, overflow := uint256.FromBig(msg.GasPrice)
+		if overflow || msg.GasPrice.Sign() < 0 {
+			return nil, errors.New("invalid gas price")
+		}
+		tx.GasPrice = gasPrice.Bytes32()
+	}
+	return tx, nil
+}
+
+// getRpcContextAtHeight returns the state and the block at `height`, the caller must hold stateMtx
+func (app *SbchFollower) getRpcContextAtHeight(height int64) (*moevmtypes.Context, *moevmtypes.BlockInfo, error) {
+	bi, ok := app.blockInfo.Load().(*moevmtypes.BlockInfo)
+	if !ok {
+		return nil, nil, errors.New("no block is followed yet")
+	}
+	if height == LatestHeight || height == bi.Number {
+		return app.getRpcContext(), bi, nil
+	}
+	if height < 0 || height > bi.Number {
+		return nil, nil, fmt.Errorf("height %d is not followed yet, the latest is %d", height, bi.Number)
+	}
+	if !app.followerConfig.ArchiveMode {
+		return nil, nil, ErrHistoricalStateUnavailable
+	}
+
+	blkBz := app.historyStore.GetBlockByHeight(height)
+	if blkBz == nil {
+		return nil, nil, fmt.Errorf("block %d is not found", height)
+	}
+	var blk moevmtypes.Block
+	if _, err := blk.UnmarshalMsg(blkBz); err != nil {
+		return nil, nil, err
+	}
+
+	c := moevmtypes.NewContext(nil, nil)
+	r := rabbit.NewReadOnlyRabbitStoreAtHeight(app.root, uint64(height))
+	c = c.WithRbt(&r)
+	c = c.WithDb(app.historyStore)
+	c.SetCurrentHeight(height)
+	return c, &moevmtypes.BlockInfo{
+		Coinbase:  blk.Miner,
+		Number:    blk.Number,
+		Timestamp: blk.Timestamp,
+		ChainId:   app.sbchChainId.Bytes32(),
+		Hash:      blk.Hash,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package follower
+
+import (
+	"math/big"
+	"testing"
+
+	geth "github.com/ethereum/go-ethereum"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+	moevmtypes "github.com/smartbch/moeingevm/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxToRunOf(t *testing.T) {
+	to := gethcmn.HexToAddress("0x423403784Ca5bD868731d604Ad097f126B36CAe2")
+
+	_, err := txToRunOf(geth.CallMsg{Data: []byte{1}})
+	require.Error(t, err)
+
+	tx, err := txToRunOf(geth.CallMsg{To: &to, Data: []byte{1, 2}, Value: big.NewInt(100)})
+	require.NoError(t, err)
+	require.Equal(t, to, tx.To)
+	require.Equal(t, []byte{1, 2}, tx.Data)
+	require.EqualValues(t, CallGasLimit, tx.Gas)
+	require.Equal(t, uint256.NewInt(100).Bytes32(), tx.Value)
+
+	tx, err = txToRunOf(geth.CallMsg{To: &to, Gas: 50000})
+	require.NoError(t, err)
+	require.EqualValues(t, 50000, tx.Gas)
+	tx, err = txToRunOf(geth.CallMsg{To: &to, Gas: CallGasLimit + 1})
+	require.NoError(t, err)
+	require.EqualValues(t, CallGasLimit, tx.Gas)
+
+	_, err = txToRunOf(geth.CallMsg{To: &to, Value: big.NewInt(-1)})
+	require.Error(t, err)
+	_, err = txToRunOf(geth.CallMsg{To: &to, Value: new(big.Int).Lsh(big.NewInt(1), 256)})
+	require.Error(t, err)
+}
+
+func TestGetRpcContextAtHeight(t *testing.T) {
+	app := &SbchFollower{followerConfig: DefaultConfig(t.TempDir(), nil, nil)}
+	_, _, err := app.getRpcContextAtHeight(LatestHeight)
+	require.Error(t, err)
+
+	app.blockInfo.Store(&moevmtypes.BlockInfo{Number: 10})
+	_, _, err = app.getRpcContextAtHeight(11)
+	require.Error(t, err)
+	_, _, err = app.getRpcContextAtHeight(-2)
+	require.Error(t, err)
+	_, _, err = app.getRpcContextAtHeight(9)
+	require.ErrorIs(t, err, ErrHistoricalStateUnavailable)
+}
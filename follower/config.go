@@ -12,8 +12,10 @@ const (
 	DefaultTrunkCacheSize          = 200
 	DefaultPruneEveryN             = 10
 
-	AppDataPath  = "app"
-	ModbDataPath = "modb"
+	AppDataPath           = "app"
+	ModbDataPath          = "modb"
+	ValidatorSetsDataPath = "validator_sets"
+	WalletLedgerDataPath  = "wallet_ledger"
 )
 
 type AppConfig struct {
@@ -22,6 +24,10 @@ type AppConfig struct {
 	//app config:
 	AppDataPath  string `mapstructure:"app_data_path"`
 	ModbDataPath string `mapstructure:"modb_data_path"`
+	// the history of the elected validator sets
+	ValidatorSetsDataPath string `mapstructure:"validator_sets_data_path"`
+	// the events of the wallets in StochasticPayVRF
+	WalletLedgerDataPath string `mapstructure:"wallet_ledger_data_path"`
 	// rpc config
 	RpcEthGetLogsMaxResults int `mapstructure:"get_logs_max_results"`
 	// Use LiteDB instead of MoDB
@@ -31,10 +37,11 @@ type AppConfig struct {
 	// the number of kept recent blocks for moeingdb
 	NumKeptBlocksInMoDB int64 `mapstructure:"blocks_kept_modb"`
 	// the entry count of the signature cache
-	TrunkCacheSize int    `mapstructure:"trunk_cache_size"`
-	PruneEveryN    int64  `mapstructure:"prune_every_n"`
-	SmartBchRPCUrl string `mapstructure:"smartbch-rpc-url"`
-	SmartBchWsUrl  string `mapstructure:"smartbch-ws-url"`
+	TrunkCacheSize int   `mapstructure:"trunk_cache_size"`
+	PruneEveryN    int64 `mapstructure:"prune_every_n"`
+	// the upstream smartBCH nodes, the next one is tried when one fails
+	SmartBchRPCUrls []string `mapstructure:"smartbch-rpc-urls"`
+	SmartBchWsUrls  []string `mapstructure:"smartbch-ws-urls"` // the logs are polled over SmartBchRPCUrls if it is empty
 
 	ArchiveMode bool `mapstructure:"archive-mode"`
 	// Output level for logging
@@ -45,24 +52,20 @@ type ChainConfig struct {
 	*AppConfig `mapstructure:"app_config"`
 }
 
-func DefaultConfig(home, sbchRpcUrl, sbchWsUrl string) *ChainConfig {
+func DefaultConfig(home string, sbchRpcUrls, sbchWsUrls []string) *ChainConfig {
 	c := &ChainConfig{
-		AppConfig: DefaultAppConfig(home, sbchRpcUrl, sbchWsUrl),
+		AppConfig: DefaultAppConfig(home, sbchRpcUrls, sbchWsUrls),
 	}
 	return c
 }
 
-func DefaultAppConfig(home, sbchRpcUrl, sbchWsUrl string) *AppConfig {
+func DefaultAppConfig(home string, sbchRpcUrls, sbchWsUrls []string) *AppConfig {
 	if home == "" {
 		home = os.ExpandEnv("$HOME/.follower")
 	}
 
-	if sbchRpcUrl == "" {
-		sbchRpcUrl = "http://0.0.0.0:8545"
-	}
-
-	if sbchWsUrl == "" {
-		sbchWsUrl = "ws://0.0.0.0:8546"
+	if len(sbchRpcUrls) == 0 {
+		sbchRpcUrls = []string{"http://0.0.0.0:8545"}
 	}
 
 	return &AppConfig{
@@ -70,13 +73,15 @@ func DefaultAppConfig(home, sbchRpcUrl, sbchWsUrl string) *AppConfig {
 		GenesisFilePath:         filepath.Join(home, "config", "genesis.json"),
 		AppDataPath:             filepath.Join(home, "data", AppDataPath),
 		ModbDataPath:            filepath.Join(home, "data", ModbDataPath),
+		ValidatorSetsDataPath:   filepath.Join(home, "data", ValidatorSetsDataPath),
+		WalletLedgerDataPath:    filepath.Join(home, "data", WalletLedgerDataPath),
 		RpcEthGetLogsMaxResults: DefaultRpcEthGetLogsMaxResults,
 		NumKeptBlocks:           DefaultNumKeptBlocks,
 		NumKeptBlocksInMoDB:     DefaultNumKeptBlocksInMoDB,
 		TrunkCacheSize:          DefaultTrunkCacheSize,
 		PruneEveryN:             DefaultPruneEveryN,
-		SmartBchRPCUrl:          sbchRpcUrl,
-		SmartBchWsUrl:           sbchWsUrl,
+		SmartBchRPCUrls:         sbchRpcUrls,
+		SmartBchWsUrls:          sbchWsUrls,
 		LogLevel:                "info",
 	}
 }
@@ -1,52 +1,50 @@
 package follower
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 
 	gethcmn "github.com/ethereum/go-ethereum/common"
-	gethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/holiman/uint256"
 	mevmtypes "github.com/smartbch/moeingevm/types"
+
+	"github.com/smartbch/ganychain/utils/storagelayout"
 )
 
 const (
 	// TODO: change it if necessary
 	// ganyaccount
-	GanyAccountContractSeq   = 288
-	DelegatedAccountsMapSlot = 0
+	GanyAccountContractSeq = 288
 
 	// stochasticpay
 	StochasticPayVRFSeq = 506
 
 	// ganygov
-	GanyGovSeq     = 509
-	ValidatorsSlot = 1
-	ValidatorWords = 9
+	GanyGovSeq = 509
 )
 
 /*
 	mapping(address => address) private delegatedAccountsMap; // main account => delegated account
 */
 
-// Note: `mainAddr` must be valid hex address
 func getDelegatedAddrByMainAddr(ctx *mevmtypes.Context, mainAddress gethcmn.Address) (gethcmn.Address, error) {
-	addrBz, err := gethcmn.ParseHexOrString(mainAddress.Hex())
+	accounts, err := storagelayout.NewReader(ganyAccountLayout, ctx, GanyAccountContractSeq).Var("delegatedAccountsMap")
 	if err != nil {
 		return gethcmn.Address{}, err
 	}
-
-	keyByte32 := uint256.NewInt(0).SetBytes(addrBz).PaddedBytes(32)
-	posByte32 := uint256.NewInt(DelegatedAccountsMapSlot).PaddedBytes(32)
-	var bzNeedToHash [64]byte
-	copy(bzNeedToHash[:32], keyByte32)
-	copy(bzNeedToHash[32:], posByte32)
-	accountLoc := uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(bzNeedToHash[:]))
-	result := ctx.GetStorageAt(GanyAccountContractSeq, string(accountLoc.PaddedBytes(32)))
-	if result == nil {
+	account, err := accounts.Key(mainAddress.Bytes())
+	if err != nil {
+		return gethcmn.Address{}, err
+	}
+	resultAddr, err := account.Address()
+	if err != nil {
+		return gethcmn.Address{}, err
+	}
+	if resultAddr == (gethcmn.Address{}) {
 		return gethcmn.Address{}, errors.New("cannot find account in storage")
 	}
-	resultAddr := gethcmn.BytesToAddress(result[12:])
 	return resultAddr, nil
 }
 
@@ -114,8 +112,26 @@ type ValidatorInfo struct {
 	OldElectedTime *uint256.Int
 }
 
-func getValidatorPubKeyList(ctx *mevmtypes.Context) [][]byte {
-	validatorInfos := readValidatorInfos(ctx, GanyGovSeq)
+// IsElected reports whether the validator is in the current validator set
+func (vi *ValidatorInfo) IsElected() bool {
+	return !vi.ElectedTime.IsZero()
+}
+
+// RpcUrlString returns the ip:port of the gateway of the validator
+func (vi *ValidatorInfo) RpcUrlString() string {
+	return bytes32ToString(vi.RpcUrl)
+}
+
+func (vi *ValidatorInfo) IntroString() string {
+	return bytes32ToString(vi.Intro)
+}
+
+// the strings in bytes32 are right-padded with zeros
+func bytes32ToString(bz []byte) string {
+	return string(bytes.TrimRight(bz, "\x00"))
+}
+
+func pubKeysOf(validatorInfos []*ValidatorInfo) [][]byte {
 	result := make([][]byte, 0, len(validatorInfos))
 	for _, vi := range validatorInfos {
 		result = append(result, vi.Pubkey)
@@ -123,38 +139,66 @@ func getValidatorPubKeyList(ctx *mevmtypes.Context) [][]byte {
 	return result
 }
 
-func readValidatorInfos(ctx *mevmtypes.Context, seq uint64) []*ValidatorInfo {
-	arrSlot := uint256.NewInt(ValidatorsSlot).PaddedBytes(32)
-	arrLen := uint256.NewInt(0).SetBytes(ctx.GetStorageAt(seq, string(arrSlot)))
-	arrLoc := uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(arrSlot))
+func readValidatorInfos(storage storagelayout.Storage, seq uint64) ([]*ValidatorInfo, error) {
+	validators, err := storagelayout.NewReader(ganyGovLayout, storage, seq).Var("validators")
+	if err != nil {
+		return nil, err
+	}
+	n, err := validators.Len()
+	if err != nil {
+		return nil, err
+	}
 
-	result := make([]*ValidatorInfo, 0, arrLen.Uint64())
-	for i := uint64(0); i < arrLen.Uint64(); i++ {
-		loc := uint256.NewInt(0).AddUint64(arrLoc, i*ValidatorWords)
-		result = append(result, readValidatorInfo(ctx, seq, loc))
+	result := make([]*ValidatorInfo, 0, n)
+	for i := uint64(0); i < n; i++ {
+		validator, err := validators.Index(i)
+		if err != nil {
+			return nil, err
+		}
+		vi, err := readValidatorInfo(validator)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, vi)
 	}
-	return result
+	return result, nil
 }
 
-func readValidatorInfo(ctx *mevmtypes.Context, seq uint64, loc *uint256.Int) *ValidatorInfo {
-	addr := ctx.GetStorageAt(seq, string(loc.PaddedBytes(32)))                             // slot#0
-	pubkeyPrefix := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32)))   // slot#1
-	pubkeyX := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32)))        // slot#2
-	rpcUrl := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32)))         // slot#3
-	intro := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32)))          // slot#4
-	totalStakedAmt := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32))) // slot#5
-	selfStakedAmt := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32)))  // slot#6
-	electedTime := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32)))    // slot#7
-	oldElectedTime := ctx.GetStorageAt(seq, string(loc.AddUint64(loc, 1).PaddedBytes(32))) // slot#8
+func readValidatorInfo(validator *storagelayout.Value) (*ValidatorInfo, error) {
+	words := make(map[string][]byte, 8)
+	for _, label := range []string{"addr", "pubkeyX", "rpcUrl", "intro",
+		"totalStakedAmt", "selfStakedAmt", "electedTime", "oldElectedTime"} {
+
+		member, err := validator.Member(label)
+		if err != nil {
+			return nil, err
+		}
+		if words[label], err = member.Bytes(); err != nil {
+			return nil, err
+		}
+	}
+	// the prefix is read as an integer, so it does not depend on the size declared in the layout
+	member, err := validator.Member("pubkeyPrefix")
+	if err != nil {
+		return nil, err
+	}
+	pubkeyPrefix, err := member.Uint256()
+	if err != nil {
+		return nil, err
+	}
 
-	return &ValidatorInfo{
-		Addr:           gethcmn.BytesToAddress(addr),
-		Pubkey:         append(pubkeyPrefix[31:], pubkeyX...),
-		RpcUrl:         rpcUrl[:],
-		Intro:          intro[:],
-		TotalStakedAmt: uint256.NewInt(0).SetBytes(totalStakedAmt),
-		SelfStakedAmt:  uint256.NewInt(0).SetBytes(selfStakedAmt),
-		ElectedTime:    uint256.NewInt(0).SetBytes(electedTime),
-		OldElectedTime: uint256.NewInt(0).SetBytes(oldElectedTime),
+	pubkey := append([]byte{byte(pubkeyPrefix.Uint64())}, words["pubkeyX"]...)
+	if len(pubkey) != 33 {
+		return nil, fmt.Errorf("invalid length of validator public key: %d", len(pubkey))
 	}
+	return &ValidatorInfo{
+		Addr:           gethcmn.BytesToAddress(words["addr"]),
+		Pubkey:         pubkey,
+		RpcUrl:         words["rpcUrl"],
+		Intro:          words["intro"],
+		TotalStakedAmt: uint256.NewInt(0).SetBytes(words["totalStakedAmt"]),
+		SelfStakedAmt:  uint256.NewInt(0).SetBytes(words["selfStakedAmt"]),
+		ElectedTime:    uint256.NewInt(0).SetBytes(words["electedTime"]),
+		OldElectedTime: uint256.NewInt(0).SetBytes(words["oldElectedTime"]),
+	}, nil
 }
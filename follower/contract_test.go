@@ -0,0 +1,127 @@
+package follower
+
+import (
+	"fmt"
+	"testing"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartbch/ganychain/utils/storagelayout"
+)
+
+type mapStorage map[string][]byte
+
+func (s mapStorage) GetStorageAt(seq uint64, key string) []byte {
+	return s[key]
+}
+
+func (s mapStorage) set(slot *uint256.Int, value []byte) {
+	s[string(slot.PaddedBytes(32))] = value
+}
+
+func TestReadValidatorInfos(t *testing.T) {
+	addr := gethcmn.HexToAddress("0x423403784Ca5bD868731d604Ad097f126B36CAe2")
+	pubkeyX := gethcrypto.Keccak256([]byte("pubkeyX"))
+
+	// validators = [{...}, {... not elected}]
+	storage := mapStorage{}
+	storage.set(uint256.NewInt(1), uint256.NewInt(2).PaddedBytes(32))
+	start := uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(uint256.NewInt(1).PaddedBytes(32)))
+	for i, electedTime := range []uint64{1650000000, 0} {
+		base := uint256.NewInt(0).AddUint64(start, uint64(i)*9)
+		words := [][]byte{
+			gethcmn.LeftPadBytes(addr.Bytes(), 32),
+			uint256.NewInt(2).PaddedBytes(32),
+			pubkeyX,
+			gethcmn.RightPadBytes([]byte("10.0.0.1:8545"), 32),
+			gethcmn.RightPadBytes([]byte("gany validator"), 32),
+			uint256.NewInt(300).PaddedBytes(32),
+			uint256.NewInt(100).PaddedBytes(32),
+			uint256.NewInt(electedTime).PaddedBytes(32),
+			uint256.NewInt(1640000000).PaddedBytes(32),
+		}
+		for j, word := range words {
+			storage.set(uint256.NewInt(0).AddUint64(base, uint64(j)), word)
+		}
+	}
+
+	validators, err := readValidatorInfos(storage, GanyGovSeq)
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+	vi := validators[0]
+	require.Equal(t, addr, vi.Addr)
+	require.Equal(t, append([]byte{2}, pubkeyX...), vi.Pubkey)
+	require.Equal(t, "10.0.0.1:8545", vi.RpcUrlString())
+	require.Equal(t, "gany validator", vi.IntroString())
+	require.EqualValues(t, 300, vi.TotalStakedAmt.Uint64())
+	require.EqualValues(t, 100, vi.SelfStakedAmt.Uint64())
+	require.EqualValues(t, 1640000000, vi.OldElectedTime.Uint64())
+	require.True(t, vi.IsElected())
+	require.False(t, validators[1].IsElected())
+	require.Equal(t, [][]byte{vi.Pubkey, validators[1].Pubkey}, pubKeysOf(validators))
+}
+
+// validatorLayout declares ValidatorInfo with the types of pubkeyPrefix and pubkeyX
+func validatorLayout(prefixType, prefixSize, xType, xSize string) *storagelayout.Layout {
+	return storagelayout.MustParseLayout(fmt.Sprintf(`{
+  "storage": [
+    {"label": "validators", "offset": 0, "slot": "1", "type": "t_array(t_struct(ValidatorInfo)_storage)dyn_storage"}
+  ],
+  "types": {
+    "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+    "t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+    "t_bytes32": {"encoding": "inplace", "label": "bytes32", "numberOfBytes": "32"},
+    "t_prefix": {"encoding": "inplace", "label": "%s", "numberOfBytes": "%s"},
+    "t_x": {"encoding": "inplace", "label": "%s", "numberOfBytes": "%s"},
+    "t_array(t_struct(ValidatorInfo)_storage)dyn_storage": {
+      "encoding": "dynamic_array", "base": "t_struct(ValidatorInfo)_storage",
+      "label": "struct GanyGov.ValidatorInfo[]", "numberOfBytes": "32"
+    },
+    "t_struct(ValidatorInfo)_storage": {
+      "encoding": "inplace", "label": "struct GanyGov.ValidatorInfo", "numberOfBytes": "288",
+      "members": [
+        {"label": "addr", "offset": 0, "slot": "0", "type": "t_address"},
+        {"label": "pubkeyPrefix", "offset": 0, "slot": "1", "type": "t_prefix"},
+        {"label": "pubkeyX", "offset": 0, "slot": "2", "type": "t_x"},
+        {"label": "rpcUrl", "offset": 0, "slot": "3", "type": "t_bytes32"},
+        {"label": "intro", "offset": 0, "slot": "4", "type": "t_bytes32"},
+        {"label": "totalStakedAmt", "offset": 0, "slot": "5", "type": "t_uint256"},
+        {"label": "selfStakedAmt", "offset": 0, "slot": "6", "type": "t_uint256"},
+        {"label": "electedTime", "offset": 0, "slot": "7", "type": "t_uint256"},
+        {"label": "oldElectedTime", "offset": 0, "slot": "8", "type": "t_uint256"}
+      ]
+    }
+  }
+}`, prefixType, prefixSize, xType, xSize))
+}
+
+func TestReadValidatorInfoLayouts(t *testing.T) {
+	pubkeyX := gethcrypto.Keccak256([]byte("pubkeyX"))
+	storage := mapStorage{}
+	storage.set(uint256.NewInt(1), uint256.NewInt(1).PaddedBytes(32))
+	start := uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(uint256.NewInt(1).PaddedBytes(32)))
+	storage.set(uint256.NewInt(0).AddUint64(start, 1), uint256.NewInt(3).PaddedBytes(32))
+	storage.set(uint256.NewInt(0).AddUint64(start, 2), pubkeyX)
+
+	readFirst := func(layout *storagelayout.Layout) (*ValidatorInfo, error) {
+		validators, err := storagelayout.NewReader(layout, storage, GanyGovSeq).Var("validators")
+		require.NoError(t, err)
+		validator, err := validators.Index(0)
+		require.NoError(t, err)
+		return readValidatorInfo(validator)
+	}
+
+	// the prefix may be declared as uint8
+	for _, prefixType := range [][2]string{{"uint256", "32"}, {"uint8", "1"}} {
+		vi, err := readFirst(validatorLayout(prefixType[0], prefixType[1], "bytes32", "32"))
+		require.NoError(t, err)
+		require.Equal(t, append([]byte{3}, pubkeyX...), vi.Pubkey)
+	}
+
+	// the public key is not 33 bytes
+	_, err := readFirst(validatorLayout("uint8", "1", "bytes16", "16"))
+	require.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"os"
 	"path"
 	"sync"
@@ -12,12 +13,11 @@ import (
 	geth "github.com/ethereum/go-ethereum"
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	gethcore "github.com/ethereum/go-ethereum/core"
-	gethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/holiman/uint256"
 	"github.com/smartbch/moeingads"
 	"github.com/smartbch/moeingads/store"
 	"github.com/smartbch/moeingads/store/rabbit"
+	storetypes "github.com/smartbch/moeingads/store/types"
 	"github.com/smartbch/moeingdb/modb"
 	modbtypes "github.com/smartbch/moeingdb/types"
 	moevmtypes "github.com/smartbch/moeingevm/types"
@@ -25,13 +25,18 @@ import (
 	tmtypes "github.com/tendermint/tendermint/types"
 	"go.uber.org/atomic"
 
-	"github.com/smartbch/ganychain/contract"
 	"github.com/smartbch/ganychain/web3client"
 )
 
 const (
 	SBCHChainId = 10001
 
+	// the gas limit of the read-only calls
+	CallGasLimit = 10000000
+
+	// how often the height of the upstream node is read after catching up
+	UpstreamHeightCheckInterval = 10 * time.Second
+
 	// event ValidatorsElect(uint indexed electedTime);
 	EventGanyGovElectValidatorTopic0 = "0xd246c04483b27b20b1e0306e279de895a4a048e1e831d07f4da5e4c9253459d3"
 )
@@ -45,18 +50,31 @@ type SbchFollower struct {
 	sbchChainId    *uint256.Int
 
 	mads         *moeingads.MoeingADS
-	root         *store.RootStore
+	root         storetypes.RootStoreI // *store.RootStore, which is mocked in the tests
 	trunk        *store.TrunkStore
 	historyStore modbtypes.DB
 
+	// stateMtx guards the followed state, the readers never see the state in the middle of a block or a rollback
+	stateMtx   sync.RWMutex
 	sbchHeight int64
 	block      *moevmtypes.Block
 	blockInfo  atomic.Value // to store *moevmtypes.BlockInfo
+	journal    journal      // to roll back the latest blocks if the upstream chain is reorganized
 	sbchClient web3client.Web3Client
 
+	// sync status
+	upstreamHeight atomic.Int64
+	catchingUp     atomic.Bool
+
 	// cached status
-	mu                  sync.RWMutex // guards cached status
-	validatorPubKeyList [][]byte
+	mu                      sync.RWMutex // guards cached status
+	validators              []*ValidatorInfo
+	validatorPubKeyList     [][]byte
+	prevValidatorPubKeyList [][]byte // the validator set before the last election
+	electedTime             int64    // the time of the last election
+
+	validatorSets *validatorSetStore
+	walletLedger  *walletLedger
 }
 
 func NewSbchFollower(followerConfig *ChainConfig, logger tmlog.Logger, sbchClient web3client.Web3Client) FollowerService {
@@ -77,10 +95,24 @@ func NewSbchFollower(followerConfig *ChainConfig, logger tmlog.Logger, sbchClien
 
 	// init the cache data
 	app.validatorPubKeyList = make([][]byte, 0, 4)
+	validatorSets, err := openValidatorSetStore(followerConfig.ValidatorSetsDataPath)
+	if err != nil {
+		panic(err)
+	}
+	app.validatorSets = validatorSets
+	app.loadLastElection()
+	if app.walletLedger, err = openWalletLedger(followerConfig.WalletLedgerDataPath, app.sbchHeight); err != nil {
+		panic(err)
+	}
 
 	// start follower
-	go app.runFollower(app.sbchHeight)
-	go app.runLogCatcher(followerConfig.SmartBchWsUrl)
+	storeHeight := app.sbchHeight
+	go app.runFollower(storeHeight)
+	// once the election log is caught, read the validator list and cache it.
+	// On the first start, the elections after the stored height are caught, the ones before it are in the state.
+	catcher := newLogCatcher(followerConfig.SmartBchRPCUrls, followerConfig.SmartBchWsUrls, app.validatorSets,
+		uint64(storeHeight), app.onValidatorsElected, app.logger.With("module", "log-catcher"))
+	go catcher.run(context.Background())
 	return app
 }
 
@@ -168,11 +200,20 @@ func (app *SbchFollower) createGenesisAccounts(alloc gethcore.GenesisAlloc) {
 func (app *SbchFollower) runFollower(storeHeight int64) {
 	app.logger.Info("Run follower", "storeHeight", storeHeight)
 	// 1. fetch blocks until catch up leader.
+	app.catchingUp.Store(true)
 	latestHeight := app.catchupLeader(storeHeight)
 	// Run 2 times to catch blocks mint amount 1st catchupLeader running.
 	latestHeight = app.catchupLeader(latestHeight)
+	app.catchingUp.Store(false)
 	// 2. keep sync with leader.
+	lastCheck := time.Now()
 	for {
+		if time.Since(lastCheck) > UpstreamHeightCheckInterval {
+			if upstreamHeight, err := app.sbchClient.GeLatestBlockHeight(); err == nil {
+				app.upstreamHeight.Store(upstreamHeight)
+			}
+			lastCheck = time.Now()
+		}
 		latestHeight = app.updateState(latestHeight + 1)
 		time.Sleep(100 * time.Millisecond)
 	}
@@ -184,20 +225,49 @@ func (app *SbchFollower) updateState(height int64) int64 {
 		app.logger.Debug("updateState failed", "wantedHeight", height, "error", err)
 		return height - 1
 	}
+	block := &moevmtypes.Block{}
+	_, err = block.UnmarshalMsg(blk.BlockInfo)
+	if err != nil {
+		panic(err)
+	}
+
+	// the upstream chain is reorganized, the latest followed block is orphaned
+	if parentHash, ok := app.latestBlockHash(height - 1); ok && parentHash != block.ParentHash {
+		app.logger.Error("parent hash mismatch", "height", height,
+			"parentHash", gethcmn.Hash(block.ParentHash).Hex(), "followedHash", gethcmn.Hash(parentHash).Hex())
+		return app.rollback(height - 1)
+	}
+
+	app.stateMtx.Lock()
+	app.journal.append(journalEntry{block: block, undoOfADS: undoOfADS(blk.UpdateOfADS, app.root.Get)})
 	app.root.SetHeight(height)
 	store.SyncUpdateTo(blk.UpdateOfADS, app.root)
 	app.historyStore.AddBlock(&blk.Block, -1, blk.Txid2sigMap)
 	app.sbchHeight = blk.Height
-	app.block = &moevmtypes.Block{}
-	_, err = app.block.UnmarshalMsg(blk.BlockInfo)
-	if err != nil {
-		panic(err)
-	}
+	app.block = block
 	app.syncBlockInfo()
+	app.stateMtx.Unlock()
+	app.indexWalletEvents(&blk.Block, block.Timestamp)
+	if height > app.upstreamHeight.Load() {
+		app.upstreamHeight.Store(height)
+	}
 	app.logger.Info("updateState done", "latestHeight", height)
 	return height
 }
 
+// latestBlockHash returns the hash of the latest followed block, which is at `height`
+func (app *SbchFollower) latestBlockHash(height int64) ([32]byte, bool) {
+	if app.block != nil && app.block.Number == height {
+		return app.block.Hash, true
+	}
+	// after restarting
+	if height > 0 && height == app.sbchHeight {
+		hash := app.historyStore.GetBlockHashByHeight(height)
+		return hash, hash != [32]byte{}
+	}
+	return [32]byte{}, false
+}
+
 func (app *SbchFollower) catchupLeader(storeHeight int64) int64 {
 	latestHeight, err := app.sbchClient.GeLatestBlockHeight()
 	for err != nil {
@@ -206,6 +276,7 @@ func (app *SbchFollower) catchupLeader(storeHeight int64) int64 {
 		latestHeight, err = app.sbchClient.GeLatestBlockHeight()
 	}
 	app.logger.Info("catchupLeader", "latestHeight", latestHeight)
+	app.upstreamHeight.Store(latestHeight)
 	for h := storeHeight + 1; h <= latestHeight; h++ {
 		h = app.updateState(h)
 	}
@@ -234,41 +305,6 @@ func (app *SbchFollower) getRpcContext() *moevmtypes.Context {
 	return c
 }
 
-func (app *SbchFollower) runLogCatcher(wsUrl string) {
-	// 1. start the websocket to listen to the logs
-	// 2. once the election log is identified, read the validator list and cache it
-	app.logger.Info("Run log catcher")
-
-	wsClient, err := ethclient.Dial(wsUrl)
-	if err != nil {
-		panic(err)
-	}
-
-	logs := make(chan gethtypes.Log)
-	sub, err := wsClient.SubscribeFilterLogs(context.Background(), geth.FilterQuery{
-		Addresses: []gethcmn.Address{contract.GanyGovAddress},
-	}, logs)
-	if err != nil {
-		panic(err)
-	}
-
-	for {
-		select {
-		case _ = <-sub.Err():
-			continue
-		case newLog := <-logs:
-			if len(newLog.Topics) == 0 {
-				continue
-			}
-
-			if newLog.Topics[0].Hex() == EventGanyGovElectValidatorTopic0 {
-				// update validator list
-				app.getValidatorPubKeyList()
-			}
-		}
-	}
-}
-
 // ---------------------------------For Backend------------------------------------------
 
 func (app *SbchFollower) GetDelegatedAddrByMainAddr(mainAddr gethcmn.Address) (gethcmn.Address, error) {
@@ -276,6 +312,8 @@ func (app *SbchFollower) GetDelegatedAddrByMainAddr(mainAddr gethcmn.Address) (g
 }
 
 func (app *SbchFollower) getDelegatedAddrByMainAddr(mainAddr gethcmn.Address) (gethcmn.Address, error) {
+	app.stateMtx.RLock()
+	defer app.stateMtx.RUnlock()
 	ctx := app.getRpcContext()
 	defer ctx.Close(false)
 	return getDelegatedAddrByMainAddr(ctx, mainAddr)
@@ -286,6 +324,8 @@ func (app *SbchFollower) LoadWalletInStochasticPay(tokenAddress, ownerAddress ge
 }
 
 func (app *SbchFollower) loadWalletInStochasticPay(tokenAddress, ownerAddress gethcmn.Address) (*uint256.Int, *uint256.Int, error) {
+	app.stateMtx.RLock()
+	defer app.stateMtx.RUnlock()
 	ctx := app.getRpcContext()
 	defer ctx.Close(false)
 	return loadWalletInStochasticPay(ctx, tokenAddress, ownerAddress)
@@ -293,22 +333,205 @@ func (app *SbchFollower) loadWalletInStochasticPay(tokenAddress, ownerAddress ge
 
 func (app *SbchFollower) GetValidatorPubKeyList() [][]byte {
 	// 1. if found in cache, return the cache list
-	if len(app.validatorPubKeyList) > 0 {
-		app.mu.RLock()
-		defer app.mu.RUnlock()
-		return app.validatorPubKeyList
+	app.mu.RLock()
+	list := app.validatorPubKeyList
+	app.mu.RUnlock()
+	if len(list) > 0 {
+		return list
 	}
 
 	// 2. if not in cache, query the store
 	return app.getValidatorPubKeyList()
 }
 
+func (app *SbchFollower) GetValidators() []*ValidatorInfo {
+	app.mu.RLock()
+	validators := app.validators
+	app.mu.RUnlock()
+	if len(validators) > 0 {
+		return validators
+	}
+
+	app.getValidatorPubKeyList()
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	return app.validators
+}
+
+func (app *SbchFollower) GetPreviousValidatorPubKeyList() ([][]byte, int64) {
+	app.mu.RLock()
+	defer app.mu.RUnlock()
+	return app.prevValidatorPubKeyList, app.electedTime
+}
+
+// GetWalletEvents returns the events of a wallet in StochasticPayVRF in [startHeight, endHeight], at most `limit` ones
+func (app *SbchFollower) GetWalletEvents(owner, token gethcmn.Address, startHeight, endHeight int64, limit int) ([]*WalletEvent, error) {
+	return app.walletLedger.query(owner, token, startHeight, endHeight, limit)
+}
+
+// indexWalletEvents indexes the followed blocks after the indexed height, which are missed after crashing or failing,
+// and then `blk`. If it fails, the blocks are indexed again after the next block is followed.
+func (app *SbchFollower) indexWalletEvents(blk *modbtypes.Block, timestamp int64) {
+	indexedHeight, err := app.walletLedger.indexedHeight()
+	for h := indexedHeight + 1; err == nil && h < blk.Height; h++ {
+		var missed *modbtypes.ExtendedBlock
+		if missed, err = app.sbchClient.GetSyncBlock(uint64(h)); err != nil {
+			break
+		}
+		block := &moevmtypes.Block{}
+		if _, err = block.UnmarshalMsg(missed.BlockInfo); err == nil {
+			err = app.indexBlockWalletEvents(&missed.Block, block.Timestamp)
+		}
+	}
+	if err == nil && blk.Height > indexedHeight {
+		err = app.indexBlockWalletEvents(blk, timestamp)
+	}
+	if err != nil {
+		app.logger.Error("indexWalletEvents failed", "height", blk.Height, "indexedHeight", indexedHeight, "error", err)
+	}
+}
+
+func (app *SbchFollower) indexBlockWalletEvents(blk *modbtypes.Block, timestamp int64) error {
+	events, err := decodeWalletEvents(blk, timestamp, func(msg geth.CallMsg) ([]byte, error) {
+		result, err := app.EthCall(msg, LatestHeight)
+		if err != nil {
+			return nil, err
+		}
+		return result.ReturnData, nil
+	}, app.logger)
+	if err != nil {
+		return err
+	}
+	return app.walletLedger.add(blk.Height, events)
+}
+
+func (app *SbchFollower) GetValidatorSetAt(height int64) (*ValidatorSet, error) {
+	return app.validatorSets.getAt(height)
+}
+
+func (app *SbchFollower) GetValidatorSetByRoot(root []byte) (*ValidatorSet, error) {
+	return app.validatorSets.getByRoot(root)
+}
+
+func (app *SbchFollower) GetValidatorSetByElectedTime(electedTime int64) (*ValidatorSet, error) {
+	return app.validatorSets.getByElectedTime(electedTime)
+}
+
+// loadLastElection restores the previous validator set, which is lost after restarting or rolling back
+func (app *SbchFollower) loadLastElection() {
+	latest, prev, err := app.validatorSets.getLatest()
+	if err != nil && !errors.Is(err, ErrValidatorSetNotFound) {
+		app.logger.Error("loadLastElection failed", "error", err)
+		return
+	}
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	app.prevValidatorPubKeyList, app.electedTime = nil, 0
+	if prev != nil {
+		app.prevValidatorPubKeyList = pubKeysOf(prev.Validators)
+	}
+	if latest != nil {
+		app.electedTime = latest.ElectedTime
+	}
+}
+
+// onValidatorsElected keeps the old validator set, the payments signed for it are accepted for a while.
+// The new set is snapshot into the history after the block of the election is followed.
+func (app *SbchFollower) onValidatorsElected(electedTime, height int64) {
+	// delivered again after restarting
+	if set, err := app.validatorSets.getAt(height); err == nil && set.Height == height && set.ElectedTime == electedTime {
+		return
+	}
+
+	// the old set must be taken before the state reaches the election, so it is never read from the state
+	app.mu.RLock()
+	prevList := app.validatorPubKeyList
+	app.mu.RUnlock()
+	if len(prevList) == 0 {
+		if latest, _, err := app.validatorSets.getLatest(); err == nil {
+			prevList = pubKeysOf(latest.Validators)
+		}
+	}
+
+	app.waitForHeight(height)
+	app.getValidatorPubKeyList()
+
+	app.mu.Lock()
+	app.prevValidatorPubKeyList = prevList
+	app.electedTime = electedTime
+	validators := app.validators
+	app.mu.Unlock()
+	app.logger.Info("validators elected", "electedTime", electedTime, "height", height, "validators", len(validators))
+
+	set, err := newValidatorSet(electedTime, height, validators)
+	if err == nil {
+		err = app.validatorSets.save(set)
+	}
+	if err != nil {
+		app.logger.Error("saving validator set failed", "height", height, "error", err)
+	}
+}
+
+func (app *SbchFollower) waitForHeight(height int64) {
+	for {
+		app.stateMtx.RLock()
+		synced := app.sbchHeight >= height
+		app.stateMtx.RUnlock()
+		if synced {
+			return
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func (app *SbchFollower) GetChainId() *uint256.Int {
+	return app.sbchChainId.Clone()
+}
+
+func (app *SbchFollower) GetLatestBlockTime() int64 {
+	bi, ok := app.blockInfo.Load().(*moevmtypes.BlockInfo)
+	if !ok {
+		return 0
+	}
+	return bi.Timestamp
+}
+
+func (app *SbchFollower) GetSyncStatus() SyncStatus {
+	app.stateMtx.RLock()
+	syncedHeight := app.sbchHeight
+	app.stateMtx.RUnlock()
+
+	status := SyncStatus{
+		SyncedHeight:    syncedHeight,
+		UpstreamHeight:  app.upstreamHeight.Load(),
+		LatestBlockTime: app.GetLatestBlockTime(),
+		State:           SyncStateLive,
+	}
+	if status.UpstreamHeight > status.SyncedHeight {
+		status.Lag = status.UpstreamHeight - status.SyncedHeight
+	}
+	if app.catchingUp.Load() {
+		status.State = SyncStateCatchingUp
+	}
+	return status
+}
+
 func (app *SbchFollower) getValidatorPubKeyList() [][]byte {
+	app.stateMtx.RLock()
+	defer app.stateMtx.RUnlock()
 	ctx := app.getRpcContext()
 	defer ctx.Close(false)
 
+	validators, err := readValidatorInfos(ctx, GanyGovSeq)
+
 	app.mu.Lock()
 	defer app.mu.Unlock()
-	app.validatorPubKeyList = getValidatorPubKeyList(ctx)
+	if err != nil {
+		app.logger.Error("getValidatorPubKeyList failed", "error", err)
+		return app.validatorPubKeyList
+	}
+	app.validators = validators
+	app.validatorPubKeyList = pubKeysOf(validators)
 	return app.validatorPubKeyList
 }
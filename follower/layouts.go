@@ -0,0 +1,56 @@
+package follower
+
+import (
+	"github.com/smartbch/ganychain/utils/storagelayout"
+)
+
+// The storage layouts of the followed contracts, output by `solc --storage-layout`.
+// Update them when the contracts are changed.
+
+// GanyAccount.sol
+//
+//	mapping(address => address) private delegatedAccountsMap; // main account => delegated account
+var ganyAccountLayout = storagelayout.MustParseLayout(`{
+  "storage": [
+    {"label": "delegatedAccountsMap", "offset": 0, "slot": "0", "type": "t_mapping(t_address,t_address)"}
+  ],
+  "types": {
+    "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+    "t_mapping(t_address,t_address)": {
+      "encoding": "mapping", "key": "t_address", "value": "t_address",
+      "label": "mapping(address => address)", "numberOfBytes": "32"
+    }
+  }
+}`)
+
+// GanyGov.sol, the variables before `validators` are not read
+//
+//	ValidatorInfo[] public validators;
+var ganyGovLayout = storagelayout.MustParseLayout(`{
+  "storage": [
+    {"label": "validators", "offset": 0, "slot": "1", "type": "t_array(t_struct(ValidatorInfo)_storage)dyn_storage"}
+  ],
+  "types": {
+    "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+    "t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+    "t_bytes32": {"encoding": "inplace", "label": "bytes32", "numberOfBytes": "32"},
+    "t_array(t_struct(ValidatorInfo)_storage)dyn_storage": {
+      "encoding": "dynamic_array", "base": "t_struct(ValidatorInfo)_storage",
+      "label": "struct GanyGov.ValidatorInfo[]", "numberOfBytes": "32"
+    },
+    "t_struct(ValidatorInfo)_storage": {
+      "encoding": "inplace", "label": "struct GanyGov.ValidatorInfo", "numberOfBytes": "288",
+      "members": [
+        {"label": "addr", "offset": 0, "slot": "0", "type": "t_address"},
+        {"label": "pubkeyPrefix", "offset": 0, "slot": "1", "type": "t_uint256"},
+        {"label": "pubkeyX", "offset": 0, "slot": "2", "type": "t_bytes32"},
+        {"label": "rpcUrl", "offset": 0, "slot": "3", "type": "t_bytes32"},
+        {"label": "intro", "offset": 0, "slot": "4", "type": "t_bytes32"},
+        {"label": "totalStakedAmt", "offset": 0, "slot": "5", "type": "t_uint256"},
+        {"label": "selfStakedAmt", "offset": 0, "slot": "6", "type": "t_uint256"},
+        {"label": "electedTime", "offset": 0, "slot": "7", "type": "t_uint256"},
+        {"label": "oldElectedTime", "offset": 0, "slot": "8", "type": "t_uint256"}
+      ]
+    }
+  }
+}`)
@@ -0,0 +1,252 @@
+package follower
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	geth "github.com/ethereum/go-ethereum"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/smartbch/ganychain/contract"
+)
+
+const (
+	LogCatcherMinBackoff   = time.Second
+	LogCatcherMaxBackoff   = time.Minute
+	LogCatcherPollInterval = 6 * time.Second // about a smartBCH block
+	LogCatcherCallTimeout  = 10 * time.Second
+
+	// the max block range of each eth_getLogs request in a backfill
+	LogBackfillBlockRange = 10000
+)
+
+// LogClient is the part of ethclient.Client used by the log catcher
+type LogClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	FilterLogs(ctx context.Context, q geth.FilterQuery) ([]gethtypes.Log, error)
+	SubscribeFilterLogs(ctx context.Context, q geth.FilterQuery, ch chan<- gethtypes.Log) (geth.Subscription, error)
+	Close()
+}
+
+func dialLogClient(url string) (LogClient, error) {
+	return ethclient.Dial(url)
+}
+
+// logProgress keeps the last block whose logs are all processed, the catcher resumes from it after restarting
+type logProgress interface {
+	getDoneBlock() (block uint64, ok bool, err error)
+	setDoneBlock(block uint64) error
+}
+
+// logCatcher watches the ValidatorsElect logs of GanyGov. It subscribes the logs over websocket,
+// and reconnects with backoff if the subscription fails. The logs missed during the reconnection
+// are backfilled with eth_getLogs, from the last processed block, which is persisted over restarts.
+// It polls with eth_getLogs if no websocket URL is configured. Each reconnection tries the next URL.
+type logCatcher struct {
+	rpcUrls   []string
+	wsUrls    []string // empty means polling over rpcUrls
+	next      int      // the index of the URL to connect
+	dial      func(url string) (LogClient, error)
+	onElected func(electedTime, height int64)
+	logger    tmlog.Logger
+
+	progress   logProgress
+	startBlock uint64 // the logs after it are caught if no progress is persisted
+	doneBlock  uint64 // the logs up to this block are all processed
+	lastBlock  uint64 // the position of the last processed log
+	lastIndex  uint
+	started    bool
+
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	pollInterval time.Duration
+}
+
+func newLogCatcher(rpcUrls, wsUrls []string, progress logProgress, startBlock uint64,
+	onElected func(electedTime, height int64), logger tmlog.Logger) *logCatcher {
+	return &logCatcher{
+		rpcUrls:      rpcUrls,
+		wsUrls:       wsUrls,
+		dial:         dialLogClient,
+		onElected:    onElected,
+		logger:       logger,
+		progress:     progress,
+		startBlock:   startBlock,
+		minBackoff:   LogCatcherMinBackoff,
+		maxBackoff:   LogCatcherMaxBackoff,
+		pollInterval: LogCatcherPollInterval,
+	}
+}
+
+func electionQuery() geth.FilterQuery {
+	return geth.FilterQuery{
+		Addresses: []gethcmn.Address{contract.GanyGovAddress},
+		Topics:    [][]gethcmn.Hash{{gethcmn.HexToHash(EventGanyGovElectValidatorTopic0)}},
+	}
+}
+
+// run never returns until ctx is done
+func (c *logCatcher) run(ctx context.Context) {
+	backoff := c.minBackoff
+	for ctx.Err() == nil {
+		var err error
+		connected := func() { backoff = c.minBackoff }
+		var url string
+		if len(c.wsUrls) == 0 {
+			url = c.rpcUrls[c.next%len(c.rpcUrls)]
+			err = c.poll(ctx, url, connected)
+		} else {
+			url = c.wsUrls[c.next%len(c.wsUrls)]
+			err = c.subscribe(ctx, url, connected)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		c.next++
+		c.logger.Error("log catcher disconnected", "url", url, "error", err, "retry after", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > c.maxBackoff {
+			backoff = c.maxBackoff
+		}
+	}
+}
+
+// subscribe returns when the subscription fails, `connected` is called after the missed logs are backfilled
+func (c *logCatcher) subscribe(ctx context.Context, url string, connected func()) error {
+	client, err := c.dial(url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// subscribe before the backfill, the logs caught by both are deduplicated by their positions
+	logs := make(chan gethtypes.Log, 16)
+	sub, err := client.SubscribeFilterLogs(ctx, electionQuery(), logs)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	if err = c.backfill(ctx, client); err != nil {
+		return err
+	}
+	c.logger.Info("log catcher subscribed", "url", url, "block", c.doneBlock)
+	connected()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err = <-sub.Err():
+			return err
+		case newLog := <-logs:
+			c.handleLog(newLog)
+		}
+	}
+}
+
+// poll returns when a request fails, `connected` is called after each successful poll
+func (c *logCatcher) poll(ctx context.Context, url string, connected func()) error {
+	client, err := c.dial(url)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for {
+		if err = c.backfill(ctx, client); err != nil {
+			return err
+		}
+		connected()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+}
+
+// backfill processes the logs after doneBlock up to the latest block.
+// When it is called for the first time, it starts from the persisted progress, or from startBlock on the first start.
+func (c *logCatcher) backfill(ctx context.Context, client LogClient) error {
+	if !c.started {
+		doneBlock, ok, err := c.progress.getDoneBlock()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			doneBlock = c.startBlock
+		}
+		c.doneBlock, c.started = doneBlock, true
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, LogCatcherCallTimeout)
+	latest, err := client.BlockNumber(callCtx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for from := c.doneBlock + 1; from <= latest; from += LogBackfillBlockRange {
+		to := from + LogBackfillBlockRange - 1
+		if to > latest {
+			to = latest
+		}
+		q := electionQuery()
+		q.FromBlock, q.ToBlock = new(big.Int).SetUint64(from), new(big.Int).SetUint64(to)
+
+		callCtx, cancel = context.WithTimeout(ctx, LogCatcherCallTimeout)
+		logs, err := client.FilterLogs(callCtx, q)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, newLog := range logs {
+			c.handleLog(newLog)
+		}
+	}
+	return c.setDoneBlock(latest)
+}
+
+// setDoneBlock advances and persists doneBlock
+func (c *logCatcher) setDoneBlock(block uint64) error {
+	if block <= c.doneBlock {
+		return nil
+	}
+	if err := c.progress.setDoneBlock(block); err != nil {
+		return err
+	}
+	c.doneBlock = block
+	return nil
+}
+
+func (c *logCatcher) handleLog(newLog gethtypes.Log) {
+	if newLog.Removed || len(newLog.Topics) == 0 || newLog.Topics[0].Hex() != EventGanyGovElectValidatorTopic0 {
+		return
+	}
+	// caught already
+	if newLog.BlockNumber <= c.doneBlock ||
+		newLog.BlockNumber < c.lastBlock || (newLog.BlockNumber == c.lastBlock && newLog.Index <= c.lastIndex) {
+		return
+	}
+	c.lastBlock, c.lastIndex = newLog.BlockNumber, newLog.Index
+
+	electedTime := time.Now().Unix()
+	if len(newLog.Topics) > 1 {
+		electedTime = newLog.Topics[1].Big().Int64()
+	}
+	c.onElected(electedTime, int64(newLog.BlockNumber))
+	// the logs are delivered in order, the blocks before this one are all processed
+	if err := c.setDoneBlock(newLog.BlockNumber - 1); err != nil {
+		c.logger.Error("saving the progress of the log catcher failed", "block", newLog.BlockNumber-1, "error", err)
+	}
+}
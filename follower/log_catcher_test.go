@@ -0,0 +1,206 @@
+package follower
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	geth "github.com/ethereum/go-ethereum"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+type fakeSubscription struct {
+	errCh chan error
+}
+
+func (s *fakeSubscription) Unsubscribe()      {}
+func (s *fakeSubscription) Err() <-chan error { return s.errCh }
+
+// fakeLogClient serves the election logs of a fake chain
+type fakeLogClient struct {
+	mtx    sync.Mutex
+	head   uint64
+	logs   []gethtypes.Log
+	subCh  chan<- gethtypes.Log
+	sub    *fakeSubscription
+	synced bool // the head is read after the subscription
+}
+
+func (c *fakeLogClient) BlockNumber(context.Context) (uint64, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.synced = c.subCh != nil
+	return c.head, nil
+}
+
+func (c *fakeLogClient) FilterLogs(_ context.Context, q geth.FilterQuery) ([]gethtypes.Log, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	var logs []gethtypes.Log
+	for _, l := range c.logs {
+		if l.BlockNumber >= q.FromBlock.Uint64() && l.BlockNumber <= q.ToBlock.Uint64() {
+			logs = append(logs, l)
+		}
+	}
+	return logs, nil
+}
+
+func (c *fakeLogClient) SubscribeFilterLogs(_ context.Context, _ geth.FilterQuery, ch chan<- gethtypes.Log) (geth.Subscription, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.subCh = ch
+	c.sub = &fakeSubscription{errCh: make(chan error, 1)}
+	return c.sub, nil
+}
+
+func (c *fakeLogClient) Close() {}
+
+// addLog mines a block with an election log, which is pushed to the subscription if `push`
+func (c *fakeLogClient) addLog(electedTime int64, push bool) {
+	c.mtx.Lock()
+	c.head++
+	l := gethtypes.Log{
+		BlockNumber: c.head,
+		Topics: []gethcmn.Hash{
+			gethcmn.HexToHash(EventGanyGovElectValidatorTopic0),
+			gethcmn.BigToHash(big.NewInt(electedTime)),
+		},
+	}
+	c.logs = append(c.logs, l)
+	subCh := c.subCh
+	c.mtx.Unlock()
+	if push {
+		subCh <- l
+	}
+}
+
+func (c *fakeLogClient) disconnect() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.sub.errCh <- errors.New("connection reset")
+	c.subCh, c.synced = nil, false
+}
+
+func (c *fakeLogClient) subscribed() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.synced
+}
+
+func newTestLogProgress(t *testing.T) *validatorSetStore {
+	store, err := openValidatorSetStore(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { store.close() })
+	return store
+}
+
+func TestLogCatcherReconnect(t *testing.T) {
+	client := &fakeLogClient{head: 100}
+	var mtx sync.Mutex
+	var elected []int64
+	getElected := func() []int64 {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return append([]int64(nil), elected...)
+	}
+
+	var dialed int32
+	var dialedUrls []string
+	catcher := newLogCatcher(nil, []string{"ws://fake1", "ws://fake2"}, newTestLogProgress(t), 100, func(electedTime, height int64) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		elected = append(elected, electedTime)
+	}, tmlog.NewNopLogger())
+	catcher.minBackoff, catcher.maxBackoff = time.Millisecond, 10*time.Millisecond
+	catcher.dial = func(url string) (LogClient, error) {
+		mtx.Lock()
+		dialedUrls = append(dialedUrls, url)
+		mtx.Unlock()
+		// the first dial fails
+		if atomic.AddInt32(&dialed, 1) == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return client, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go catcher.run(ctx)
+
+	require.Eventually(t, client.subscribed, time.Second, time.Millisecond)
+	client.addLog(1000, true)
+	require.Eventually(t, func() bool { return len(getElected()) == 1 }, time.Second, time.Millisecond)
+
+	// the logs are missed while disconnected, and backfilled after the reconnection
+	client.disconnect()
+	client.addLog(2000, false)
+	client.addLog(3000, false)
+	require.Eventually(t, client.subscribed, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool { return len(getElected()) == 3 }, time.Second, time.Millisecond)
+	require.Equal(t, []int64{1000, 2000, 3000}, getElected())
+	require.EqualValues(t, 3, atomic.LoadInt32(&dialed))
+
+	// each reconnection fails over to the next URL
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Equal(t, []string{"ws://fake1", "ws://fake2", "ws://fake1"}, dialedUrls)
+}
+
+func TestLogCatcherPolling(t *testing.T) {
+	client := &fakeLogClient{head: 100}
+	elected := make(chan int64, 10)
+	catcher := newLogCatcher([]string{"http://fake"}, nil, newTestLogProgress(t), 100,
+		func(electedTime, height int64) { elected <- electedTime }, tmlog.NewNopLogger())
+	catcher.pollInterval = time.Millisecond
+	catcher.dial = func(url string) (LogClient, error) {
+		require.Equal(t, "http://fake", url)
+		return client, nil
+	}
+
+	// starts from the stored height on the first start
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, catcher.backfill(ctx, client))
+	go catcher.run(ctx)
+
+	client.addLog(1000, false)
+	require.Equal(t, int64(1000), <-elected)
+	require.Len(t, elected, 0)
+}
+
+func TestLogCatcherRestart(t *testing.T) {
+	client := &fakeLogClient{head: 100}
+	client.addLog(1000, false) // at 101, before the follower starts
+	progress := newTestLogProgress(t)
+	var elected []int64
+	newCatcher := func() *logCatcher {
+		catcher := newLogCatcher([]string{"http://fake"}, nil, progress, 100,
+			func(electedTime, height int64) { elected = append(elected, electedTime) }, tmlog.NewNopLogger())
+		catcher.dial = func(url string) (LogClient, error) { return client, nil }
+		return catcher
+	}
+
+	// the logs after the stored height of the follower are caught on the first start
+	require.NoError(t, newCatcher().backfill(context.Background(), client))
+	require.Equal(t, []int64{1000}, elected)
+	block, ok, err := progress.getDoneBlock()
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, 101, block)
+
+	// resumes from the last processed block after restarting
+	client.addLog(2000, false)
+	client.addLog(3000, false)
+	require.NoError(t, newCatcher().backfill(context.Background(), client))
+	require.Equal(t, []int64{1000, 2000, 3000}, elected)
+	block, _, err = progress.getDoneBlock()
+	require.NoError(t, err)
+	require.EqualValues(t, 103, block)
+}
@@ -0,0 +1,120 @@
+package follower
+
+import (
+	"fmt"
+	"time"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/smartbch/moeingads/store"
+	moevmtypes "github.com/smartbch/moeingevm/types"
+)
+
+// MaxRollbackDepth is the number of the latest followed blocks which can be rolled back
+const MaxRollbackDepth = 128
+
+// journalEntry is how to undo a followed block
+type journalEntry struct {
+	block *moevmtypes.Block
+	// the values of the keys in moeingads before the block, empty means the key did not exist
+	undoOfADS map[string]string
+}
+
+// journal keeps the latest followed blocks in memory, it is empty after restarting
+type journal struct {
+	entries []journalEntry // in the order of height
+}
+
+func (j *journal) append(entry journalEntry) {
+	if n := len(j.entries); n > 0 && j.entries[n-1].block.Number+1 != entry.block.Number {
+		j.entries = j.entries[:0] // not continuous, e.g. after the genesis
+	}
+	if len(j.entries) == MaxRollbackDepth {
+		j.entries[0] = journalEntry{}
+		j.entries = j.entries[1:]
+	}
+	j.entries = append(j.entries, entry)
+}
+
+func (j *journal) get(height int64) (journalEntry, bool) {
+	if len(j.entries) == 0 {
+		return journalEntry{}, false
+	}
+	i := height - j.entries[0].block.Number
+	if i < 0 || i >= int64(len(j.entries)) {
+		return journalEntry{}, false
+	}
+	return j.entries[i], true
+}
+
+// popAfter drops the entries above height, the latest one first
+func (j *journal) popAfter(height int64, undo func(entry journalEntry)) {
+	for n := len(j.entries); n > 0 && j.entries[n-1].block.Number > height; n-- {
+		undo(j.entries[n-1])
+		j.entries[n-1] = journalEntry{}
+		j.entries = j.entries[:n-1]
+	}
+}
+
+// undoOfADS reads the values overwritten by the updates
+func undoOfADS(updateOfADS map[string]string, get func(key []byte) []byte) map[string]string {
+	undo := make(map[string]string, len(updateOfADS))
+	for key := range updateOfADS {
+		undo[key] = string(get([]byte(key)))
+	}
+	return undo
+}
+
+// ----------------------------------------------------------------
+
+// findCommonHeight returns the highest height below `height` where the followed block is still on the upstream chain
+func (app *SbchFollower) findCommonHeight(height int64) int64 {
+	for h := height; h > 0; h-- {
+		entry, ok := app.journal.get(h)
+		if !ok {
+			panic(fmt.Sprintf("cannot roll back the follower: the reorg is deeper than the %d blocks kept since starting, "+
+				"resync the follower from a trusted smartBCH node", len(app.journal.entries)))
+		}
+
+		blk, err := app.sbchClient.GetSyncBlock(uint64(h))
+		for err != nil {
+			app.logger.Error("findCommonHeight failed", "height", h, "error", err)
+			time.Sleep(3 * time.Second)
+			blk, err = app.sbchClient.GetSyncBlock(uint64(h))
+		}
+		if blk.BlockHash == entry.block.Hash {
+			return h
+		}
+	}
+	panic("cannot roll back the follower: no common block with the upstream node")
+}
+
+// rollback undoes the blocks above the last common height with the upstream chain, it returns the common height.
+// moeingdb cannot be rolled back, the orphaned heights in it are added again when they are followed again,
+// and the wallets are read from moeingads only. The wallet events and the validator sets of the orphaned blocks are removed.
+func (app *SbchFollower) rollback(height int64) int64 {
+	commonHeight := app.findCommonHeight(height)
+	app.logger.Error("rolling back the orphaned blocks", "from", height, "to", commonHeight)
+
+	app.stateMtx.Lock()
+	app.journal.popAfter(commonHeight, func(entry journalEntry) {
+		app.root.SetHeight(entry.block.Number)
+		store.SyncUpdateTo(entry.undoOfADS, app.root)
+	})
+	entry, _ := app.journal.get(commonHeight)
+	app.sbchHeight = commonHeight
+	app.block = entry.block
+	app.syncBlockInfo()
+	app.stateMtx.Unlock()
+	if err := app.walletLedger.removeAfter(commonHeight); err != nil {
+		app.logger.Error("removing the wallet events of the orphaned blocks failed", "error", err)
+	}
+	if err := app.validatorSets.removeAfter(commonHeight); err != nil {
+		app.logger.Error("removing the validator sets of the orphaned blocks failed", "error", err)
+	}
+	app.loadLastElection()
+
+	// the validators may be elected on the orphaned blocks
+	app.getValidatorPubKeyList()
+	app.logger.Info("rolled back", "height", commonHeight, "hash", gethcmn.Hash(entry.block.Hash).Hex())
+	return commonHeight
+}
@@ -0,0 +1,204 @@
+package follower
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/smartbch/moeingads/store"
+	"github.com/smartbch/moeingdb/modb"
+	modbtypes "github.com/smartbch/moeingdb/types"
+	moevmtypes "github.com/smartbch/moeingevm/types"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/smartbch/ganychain/contract"
+	"github.com/smartbch/ganychain/web3client"
+)
+
+func TestJournal(t *testing.T) {
+	var j journal
+	for h := int64(1); h <= MaxRollbackDepth+10; h++ {
+		j.append(journalEntry{block: &moevmtypes.Block{Number: h}})
+	}
+	require.Len(t, j.entries, MaxRollbackDepth)
+	_, ok := j.get(10)
+	require.False(t, ok)
+	entry, ok := j.get(MaxRollbackDepth + 10)
+	require.True(t, ok)
+	require.EqualValues(t, MaxRollbackDepth+10, entry.block.Number)
+
+	// the latest block is undone first
+	var undone []int64
+	j.popAfter(MaxRollbackDepth+7, func(entry journalEntry) {
+		undone = append(undone, entry.block.Number)
+	})
+	require.Equal(t, []int64{MaxRollbackDepth + 10, MaxRollbackDepth + 9, MaxRollbackDepth + 8}, undone)
+	_, ok = j.get(MaxRollbackDepth + 8)
+	require.False(t, ok)
+
+	// followed again after the rollback
+	j.append(journalEntry{block: &moevmtypes.Block{Number: MaxRollbackDepth + 8}})
+	require.Len(t, j.entries, MaxRollbackDepth-2)
+
+	// not continuous
+	j.append(journalEntry{block: &moevmtypes.Block{Number: 1000}})
+	require.Len(t, j.entries, 1)
+}
+
+func TestUndoOfADS(t *testing.T) {
+	state := map[string]string{"a": "1", "b": "2"}
+	undo := undoOfADS(map[string]string{"a": "3", "b": "", "c": "4"}, func(key []byte) []byte {
+		if v, ok := state[string(key)]; ok {
+			return []byte(v)
+		}
+		return nil
+	})
+	// an empty value deletes the key, which is created by the block
+	require.Equal(t, map[string]string{"a": "1", "b": "2", "c": ""}, undo)
+}
+
+// forkedClient serves a chain whose blocks can be replaced to simulate a reorg
+type forkedClient struct {
+	web3client.Web3Client
+	blocks map[int64]*modbtypes.ExtendedBlock
+}
+
+func (c *forkedClient) GetSyncBlock(height uint64) (*modbtypes.ExtendedBlock, error) {
+	blk, ok := c.blocks[int64(height)]
+	if !ok {
+		return nil, errors.New("block not produced yet")
+	}
+	return blk, nil
+}
+
+// extend produces the blocks of `branch` on top of the block at `parentHeight`, each block sets "latest" to its name
+func (c *forkedClient) extend(t *testing.T, branch string, parentHeight, toHeight int64, txsAt map[int64][]modbtypes.Tx) {
+	var parentHash [32]byte
+	if parent, ok := c.blocks[parentHeight]; ok {
+		parentHash = parent.BlockHash
+	}
+	for h := parentHeight + 1; h <= toHeight; h++ {
+		name := fmt.Sprintf("%s%d", branch, h)
+		block := moevmtypes.Block{Number: h, ParentHash: parentHash, Hash: gethcrypto.Keccak256Hash([]byte(name)), Timestamp: 1000 + h}
+		info, err := block.MarshalMsg(nil)
+		require.NoError(t, err)
+		c.blocks[h] = &modbtypes.ExtendedBlock{
+			Block:       modbtypes.Block{Height: h, BlockHash: block.Hash, BlockInfo: info, TxList: txsAt[h]},
+			UpdateOfADS: map[string]string{"latest": name, name: "1"},
+		}
+		parentHash = block.Hash
+	}
+}
+
+func TestRollback(t *testing.T) {
+	client := &forkedClient{blocks: make(map[int64]*modbtypes.ExtendedBlock)}
+	app := &SbchFollower{
+		logger:       tmlog.NewNopLogger(),
+		sbchChainId:  uint256.NewInt(SBCHChainId),
+		sbchClient:   client,
+		historyStore: &modb.MockMoDB{},
+		root:         store.NewMockRootStore(),
+	}
+	var err error
+	app.walletLedger, err = openWalletLedger(t.TempDir(), 0)
+	require.NoError(t, err)
+	defer app.walletLedger.close()
+	app.validatorSets, err = openValidatorSetStore(t.TempDir())
+	require.NoError(t, err)
+	defer app.validatorSets.close()
+
+	deposit := func(amount int64) []modbtypes.Tx {
+		return []modbtypes.Tx{newTestTx(t, 0, testOwner, contract.StochasticPayVRFAddress, gethtypes.ReceiptStatusSuccessful,
+			"deposit", testOwner, packAddressAmount(testToken, amount))}
+	}
+	walletEvents := func() []int64 {
+		events, err := app.walletLedger.query(testOwner, testToken, 0, 100, 0)
+		require.NoError(t, err)
+		heights := make([]int64, 0, len(events))
+		for _, event := range events {
+			heights = append(heights, event.Height)
+		}
+		return heights
+	}
+	get := func(key string) string {
+		return string(app.root.Get([]byte(key)))
+	}
+
+	client.extend(t, "a", 0, 5, map[int64][]modbtypes.Tx{2: deposit(10), 5: deposit(20)})
+	for h := int64(1); h <= 5; h++ {
+		require.Equal(t, h, app.updateState(h))
+	}
+	require.Equal(t, "a5", get("latest"))
+	require.Equal(t, []int64{2, 5}, walletEvents())
+
+	// the same set is elected at 2 and on the orphaned block 5, and another set is elected at 4
+	v1, v2 := newTestValidator(t), newTestValidator(t)
+	set2, err := newValidatorSet(2000, 2, []*ValidatorInfo{v1})
+	require.NoError(t, err)
+	set4, err := newValidatorSet(4000, 4, []*ValidatorInfo{v2})
+	require.NoError(t, err)
+	set5, err := newValidatorSet(5000, 5, []*ValidatorInfo{v1})
+	require.NoError(t, err)
+	for _, set := range []*ValidatorSet{set2, set4, set5} {
+		require.NoError(t, app.validatorSets.save(set))
+	}
+	app.loadLastElection()
+	require.EqualValues(t, 5000, app.electedTime)
+
+	// the blocks above 3 are replaced, the follower finds it by the parent hash of the next block
+	client.extend(t, "b", 3, 6, map[int64][]modbtypes.Tx{5: deposit(30)})
+	require.EqualValues(t, 3, app.findCommonHeight(5))
+	require.EqualValues(t, 3, app.updateState(6))
+
+	require.EqualValues(t, 3, app.sbchHeight)
+	require.EqualValues(t, 3, app.block.Number)
+	require.Equal(t, client.blocks[3].BlockHash, app.block.Hash)
+	require.EqualValues(t, 1003, app.GetLatestBlockTime())
+	require.Equal(t, "a3", get("latest"))
+	require.Equal(t, "1", get("a3"))
+	require.Empty(t, get("a4"))
+	require.Empty(t, get("a5"))
+	require.Equal(t, []int64{2}, walletEvents())
+
+	// the elections on the orphaned blocks are dropped
+	latest, prev, err := app.validatorSets.getLatest()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, latest.Height)
+	require.Nil(t, prev)
+	require.EqualValues(t, 2000, app.electedTime)
+	require.Empty(t, app.prevValidatorPubKeyList)
+	set, err := app.validatorSets.getAt(5)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, set.Height)
+	_, err = app.validatorSets.getByRoot(set4.Root)
+	require.ErrorIs(t, err, ErrValidatorSetNotFound)
+	set, err = app.validatorSets.getByRoot(set5.Root)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, set.Height)
+	set, err = app.validatorSets.getByElectedTime(5000)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, set.Height)
+
+	// followed again on the new branch
+	for h := int64(4); h <= 6; h++ {
+		require.Equal(t, h, app.updateState(h))
+	}
+	require.Equal(t, "b6", get("latest"))
+	require.Empty(t, get("a4"))
+	require.Equal(t, []int64{2, 5}, walletEvents())
+
+	// the events lost in a crash before indexing are indexed after the next block is followed
+	require.NoError(t, app.walletLedger.removeAfter(4))
+	require.Equal(t, []int64{2}, walletEvents())
+	client.extend(t, "b", 6, 7, nil)
+	require.EqualValues(t, 7, app.updateState(7))
+	require.Equal(t, []int64{2, 5}, walletEvents())
+
+	// a reorg deeper than the journal cannot be rolled back
+	app.journal = journal{}
+	require.Panics(t, func() { app.findCommonHeight(6) })
+}
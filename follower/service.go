@@ -1,12 +1,52 @@
 package follower
 
 import (
+	geth "github.com/ethereum/go-ethereum"
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
 )
 
+// The states of the follower
+const (
+	SyncStateCatchingUp = "catching-up" // following the blocks produced before starting
+	SyncStateLive       = "live"
+)
+
+type SyncStatus struct {
+	SyncedHeight    int64  `json:"syncedHeight"`
+	UpstreamHeight  int64  `json:"upstreamHeight"` // the latest height of the smartBCH node followed
+	Lag             int64  `json:"lag"`            // in blocks
+	LatestBlockTime int64  `json:"latestBlockTime"`
+	State           string `json:"state"`
+}
+
 type FollowerService interface {
 	GetDelegatedAddrByMainAddr(mainAddr gethcmn.Address) (gethcmn.Address, error)
 	LoadWalletInStochasticPay(tokenAddress, ownerAddress gethcmn.Address) (*uint256.Int, *uint256.Int, error)
 	GetValidatorPubKeyList() [][]byte
+	// GetValidators returns the validators registered in the ganygov contract, the elected ones have an ElectedTime
+	GetValidators() []*ValidatorInfo
+	// GetPreviousValidatorPubKeyList returns the validator set before the last election,
+	// and the time of the election in unix seconds. It is empty if no election is caught after starting.
+	GetPreviousValidatorPubKeyList() ([][]byte, int64)
+	// GetValidatorSetAt returns the validator set in effect at the height, from the elections caught by this node
+	GetValidatorSetAt(height int64) (*ValidatorSet, error)
+	// GetValidatorSetByRoot returns the latest validator set with the Merkle root of the public keys
+	GetValidatorSetByRoot(root []byte) (*ValidatorSet, error)
+	// GetValidatorSetByElectedTime returns the validator set in effect at the unix time, which is the latest one elected at or before it
+	GetValidatorSetByElectedTime(electedTime int64) (*ValidatorSet, error)
+	GetChainId() *uint256.Int
+	// GetLatestBlockTime returns the time of the latest followed block, or 0 before any block is followed
+	GetLatestBlockTime() int64
+	// CallContract runs a read-only call on the followed state with moeingevm,
+	// it returns the output and the timestamp of the latest followed block.
+	CallContract(from, to gethcmn.Address, data []byte) ([]byte, int64, error)
+	// EthCall runs a read-only call on the followed state at the height, or at the latest block with LatestHeight.
+	// The states before the latest block are only kept in archive mode.
+	EthCall(msg geth.CallMsg, height int64) (*CallResult, error)
+	GetSyncStatus() SyncStatus
+	// GetWalletEvents returns the deposits, withdrawals and payments of a wallet in StochasticPayVRF,
+	// in [startHeight, endHeight] and in the order of height. The blocks followed before upgrading are not indexed,
+	// nor are the calls to StochasticPayVRF made by other contracts.
+	GetWalletEvents(owner, token gethcmn.Address, startHeight, endHeight int64, limit int) ([]*WalletEvent, error)
 }
@@ -0,0 +1,312 @@
+package follower
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+
+	"github.com/dgraph-io/badger/v3"
+
+	"github.com/smartbch/ganychain/utils/cryptoutils"
+)
+
+var ErrValidatorSetNotFound = errors.New("validator set not found")
+
+const (
+	validatorSetKeyPrefix  = byte('h') // h || height => ValidatorSet in JSON
+	validatorRootKeyPrefix = byte('r') // r || root => height
+	electedTimeKeyPrefix   = byte('e') // e || electedTime => height
+	logCatcherKey          = byte('c') // c => the last block whose ValidatorsElect logs are all processed
+)
+
+// ValidatorSet is a snapshot of the validators taken when a ValidatorsElect event is caught
+type ValidatorSet struct {
+	ElectedTime int64            `json:"electedTime"`
+	Height      int64            `json:"height"` // the height of the ValidatorsElect event
+	Root        []byte           `json:"root"`   // the Merkle root of the public keys, which the payments are signed for
+	Validators  []*ValidatorInfo `json:"validators"`
+}
+
+func newValidatorSet(electedTime, height int64, validators []*ValidatorInfo) (*ValidatorSet, error) {
+	tree, err := cryptoutils.NewValidatorPubKeysMerkleTree(pubKeysOf(validators))
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorSet{
+		ElectedTime: electedTime,
+		Height:      height,
+		Root:        tree.MerkleRoot(),
+		Validators:  validators,
+	}, nil
+}
+
+// validatorSetStore keeps the history of the validator sets, indexed by the height, the Merkle root and the elected time.
+// It also keeps the progress of the log catcher which delivers the elections.
+type validatorSetStore struct {
+	db *badger.DB
+}
+
+func openValidatorSetStore(dir string) (*validatorSetStore, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &validatorSetStore{db: db}, nil
+}
+
+func (s *validatorSetStore) close() error {
+	return s.db.Close()
+}
+
+func validatorSetKey(height int64) []byte {
+	key := make([]byte, 9)
+	key[0] = validatorSetKeyPrefix
+	binary.BigEndian.PutUint64(key[1:], uint64(height))
+	return key
+}
+
+func validatorRootKey(root []byte) []byte {
+	return append([]byte{validatorRootKeyPrefix}, root...)
+}
+
+func electedTimeKey(electedTime int64) []byte {
+	key := make([]byte, 9)
+	key[0] = electedTimeKeyPrefix
+	binary.BigEndian.PutUint64(key[1:], uint64(electedTime))
+	return key
+}
+
+func (s *validatorSetStore) getDoneBlock() (block uint64, ok bool, err error) {
+	err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte{logCatcherKey})
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			if len(val) != 8 {
+				return errors.New("invalid progress of log catcher")
+			}
+			block, ok = binary.BigEndian.Uint64(val), true
+			return nil
+		})
+	})
+	return
+}
+
+func (s *validatorSetStore) setDoneBlock(block uint64) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		var value [8]byte
+		binary.BigEndian.PutUint64(value[:], block)
+		return txn.Set([]byte{logCatcherKey}, value[:])
+	})
+}
+
+// save overwrites the set at the same height, which is caught again after reconnecting or rolling back
+func (s *validatorSetStore) save(set *ValidatorSet) error {
+	value, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Set(validatorSetKey(set.Height), value); err != nil {
+			return err
+		}
+		if err := txn.Set(electedTimeKey(set.ElectedTime), validatorSetKey(set.Height)[1:]); err != nil {
+			return err
+		}
+		return txn.Set(validatorRootKey(set.Root), validatorSetKey(set.Height)[1:])
+	})
+}
+
+// removeAfter drops the sets elected on the orphaned blocks above `height`. The roots of the dropped sets
+// point to the latest set left with the same root, if any.
+func (s *validatorSetStore) removeAfter(height int64) error {
+	return s.db.Update(func(txn *badger.Txn) error {
+		var keys [][]byte
+		var roots [][]byte
+		for _, prefix := range []byte{validatorSetKeyPrefix, validatorRootKeyPrefix, electedTimeKeyPrefix} {
+			opts := badger.DefaultIteratorOptions
+			opts.Prefix = []byte{prefix}
+			iter := txn.NewIterator(opts)
+			for iter.Rewind(); iter.Valid(); iter.Next() {
+				item := iter.Item()
+				var setHeight int64
+				if prefix == validatorSetKeyPrefix {
+					setHeight = int64(binary.BigEndian.Uint64(item.Key()[1:]))
+				} else {
+					heightBz, err := item.ValueCopy(nil)
+					if err != nil {
+						iter.Close()
+						return err
+					}
+					setHeight = int64(binary.BigEndian.Uint64(heightBz))
+				}
+				if setHeight <= height {
+					continue
+				}
+				keys = append(keys, item.KeyCopy(nil))
+				if prefix == validatorRootKeyPrefix {
+					roots = append(roots, item.KeyCopy(nil)[1:])
+				}
+			}
+			iter.Close()
+		}
+		for _, key := range keys {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		return restoreRoots(txn, roots, height)
+	})
+}
+
+// restoreRoots points the roots to the latest sets with them at or below `height`
+func restoreRoots(txn *badger.Txn, roots [][]byte, height int64) error {
+	if len(roots) == 0 {
+		return nil
+	}
+	opts := badger.DefaultIteratorOptions
+	opts.Reverse = true
+	opts.Prefix = []byte{validatorSetKeyPrefix}
+	iter := txn.NewIterator(opts)
+	defer iter.Close()
+
+	restored := make(map[string]bool, len(roots))
+	for iter.Seek(validatorSetKey(height)); iter.Valid() && len(restored) < len(roots); iter.Next() {
+		var set ValidatorSet
+		if err := iter.Item().Value(func(val []byte) error {
+			return json.Unmarshal(val, &set)
+		}); err != nil {
+			return err
+		}
+		for _, root := range roots {
+			if !restored[string(root)] && bytes.Equal(set.Root, root) {
+				restored[string(root)] = true
+				if err := txn.Set(validatorRootKey(root), validatorSetKey(set.Height)[1:]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getAt returns the set in effect at `height`, which is the latest one elected at or below it
+func (s *validatorSetStore) getAt(height int64) (*ValidatorSet, error) {
+	if height < 0 {
+		return nil, ErrValidatorSetNotFound
+	}
+	var set ValidatorSet
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		opts.Prefix = []byte{validatorSetKeyPrefix}
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		iter.Seek(validatorSetKey(height))
+		if !iter.Valid() {
+			return ErrValidatorSetNotFound
+		}
+		return iter.Item().Value(func(val []byte) error {
+			return json.Unmarshal(val, &set)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// getLatest returns the latest set, and the one before it
+func (s *validatorSetStore) getLatest() (latest, prev *ValidatorSet, err error) {
+	if latest, err = s.getAt(math.MaxInt64); err != nil {
+		return nil, nil, err
+	}
+	prev, err = s.getAt(latest.Height - 1)
+	if errors.Is(err, ErrValidatorSetNotFound) {
+		err = nil
+	}
+	return latest, prev, err
+}
+
+// getByRoot returns the latest set with the Merkle root, a set may be elected again
+func (s *validatorSetStore) getByRoot(root []byte) (*ValidatorSet, error) {
+	var heightBz []byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(validatorRootKey(root))
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return ErrValidatorSetNotFound
+		} else if err != nil {
+			return err
+		}
+		heightBz, err = item.ValueCopy(nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(heightBz) != 8 {
+		return nil, errors.New("invalid height of validator root")
+	}
+	height := int64(binary.BigEndian.Uint64(heightBz))
+	set, err := s.getAt(height)
+	if err != nil {
+		return nil, err
+	}
+	if set.Height != height || !bytes.Equal(set.Root, root) { // overwritten after a rollback
+		return nil, ErrValidatorSetNotFound
+	}
+	return set, nil
+}
+
+// getByElectedTime returns the set in effect at the unix time, which is the latest one elected at or before it.
+// The entries of the sets overwritten after a rollback are skipped.
+func (s *validatorSetStore) getByElectedTime(electedTime int64) (*ValidatorSet, error) {
+	if electedTime < 0 {
+		return nil, ErrValidatorSetNotFound
+	}
+	var set *ValidatorSet
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Reverse = true
+		opts.Prefix = []byte{electedTimeKeyPrefix}
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		for iter.Seek(electedTimeKey(electedTime)); iter.Valid(); iter.Next() {
+			heightBz, err := iter.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			if len(heightBz) != 8 {
+				return errors.New("invalid height of elected time")
+			}
+			item, err := txn.Get(validatorSetKey(int64(binary.BigEndian.Uint64(heightBz))))
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			var candidate ValidatorSet
+			if err = item.Value(func(val []byte) error {
+				return json.Unmarshal(val, &candidate)
+			}); err != nil {
+				return err
+			}
+			if candidate.ElectedTime == int64(binary.BigEndian.Uint64(iter.Item().Key()[1:])) {
+				set = &candidate
+				return nil
+			}
+		}
+		return ErrValidatorSetNotFound
+	})
+	if err != nil {
+		return nil, err
+	}
+	return set, nil
+}
@@ -0,0 +1,101 @@
+package follower
+
+import (
+	"testing"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestValidator(t *testing.T) *ValidatorInfo {
+	key, err := gethcrypto.GenerateKey()
+	require.NoError(t, err)
+	return &ValidatorInfo{
+		Addr:           gethcrypto.PubkeyToAddress(key.PublicKey),
+		Pubkey:         gethcrypto.CompressPubkey(&key.PublicKey),
+		RpcUrl:         gethcmn.RightPadBytes([]byte("10.0.0.1:8545"), 32),
+		Intro:          make([]byte, 32),
+		TotalStakedAmt: uint256.NewInt(300),
+		SelfStakedAmt:  uint256.NewInt(100),
+		ElectedTime:    uint256.NewInt(1650000000),
+		OldElectedTime: uint256.NewInt(0),
+	}
+}
+
+func TestValidatorSetStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openValidatorSetStore(dir)
+	require.NoError(t, err)
+	defer func() { store.close() }()
+
+	_, err = store.getAt(100)
+	require.ErrorIs(t, err, ErrValidatorSetNotFound)
+	_, _, err = store.getLatest()
+	require.ErrorIs(t, err, ErrValidatorSetNotFound)
+
+	v1, v2, v3 := newTestValidator(t), newTestValidator(t), newTestValidator(t)
+	set1, err := newValidatorSet(1000, 100, []*ValidatorInfo{v1, v2})
+	require.NoError(t, err)
+	set2, err := newValidatorSet(2000, 200, []*ValidatorInfo{v2, v3})
+	require.NoError(t, err)
+	require.NotEqual(t, set1.Root, set2.Root)
+	require.NoError(t, store.save(set1))
+	require.NoError(t, store.save(set2))
+
+	_, err = store.getAt(99)
+	require.ErrorIs(t, err, ErrValidatorSetNotFound)
+	for height, electedTime := range map[int64]int64{100: 1000, 199: 1000, 200: 2000, 1000: 2000} {
+		set, err := store.getAt(height)
+		require.NoError(t, err)
+		require.Equal(t, electedTime, set.ElectedTime)
+	}
+	set, err := store.getAt(150)
+	require.NoError(t, err)
+	require.Equal(t, set1.Root, set.Root)
+	require.Len(t, set.Validators, 2)
+	require.Equal(t, v1.Pubkey, set.Validators[0].Pubkey)
+	require.Equal(t, v1.TotalStakedAmt, set.Validators[0].TotalStakedAmt)
+	require.Equal(t, v1.RpcUrlString(), set.Validators[0].RpcUrlString())
+
+	set, err = store.getByRoot(set1.Root)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, set.Height)
+	_, err = store.getByRoot(gethcmn.Hash{}.Bytes())
+	require.ErrorIs(t, err, ErrValidatorSetNotFound)
+
+	_, err = store.getByElectedTime(999)
+	require.ErrorIs(t, err, ErrValidatorSetNotFound)
+	for electedTime, height := range map[int64]int64{1000: 100, 1999: 100, 2000: 200, 5000: 200} {
+		set, err := store.getByElectedTime(electedTime)
+		require.NoError(t, err)
+		require.Equal(t, height, set.Height)
+	}
+
+	latest, prev, err := store.getLatest()
+	require.NoError(t, err)
+	require.EqualValues(t, 200, latest.Height)
+	require.EqualValues(t, 100, prev.Height)
+
+	// the election at 200 is orphaned, and another set is elected at the same height
+	set3, err := newValidatorSet(2000, 200, []*ValidatorInfo{v1, v3})
+	require.NoError(t, err)
+	require.NoError(t, store.save(set3))
+	_, err = store.getByRoot(set2.Root)
+	require.ErrorIs(t, err, ErrValidatorSetNotFound)
+	set, err = store.getByRoot(set3.Root)
+	require.NoError(t, err)
+	require.EqualValues(t, 200, set.Height)
+
+	// the orphaned election time is skipped
+	set4, err := newValidatorSet(2500, 200, []*ValidatorInfo{v1, v3})
+	require.NoError(t, err)
+	require.NoError(t, store.save(set4))
+	set, err = store.getByElectedTime(2200)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, set.Height)
+	set, err = store.getByElectedTime(2500)
+	require.NoError(t, err)
+	require.Equal(t, set4.Root, set.Root)
+}
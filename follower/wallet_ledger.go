@@ -0,0 +1,402 @@
+package follower
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dgraph-io/badger/v3"
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	modbtypes "github.com/smartbch/moeingdb/types"
+	moevmtypes "github.com/smartbch/moeingevm/types"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/smartbch/ganychain/contract"
+)
+
+// The types of the wallet events
+const (
+	WalletEventDeposit             = "deposit"
+	WalletEventWithdrawal          = "withdrawal"
+	WalletEventPayToAB             = "payToAB"
+	WalletEventPayToSingleReceiver = "payToSingleReceiver"
+)
+
+// MaxWalletEventsPerQuery limits the events returned by a query
+const MaxWalletEventsPerQuery = 1000
+
+// errGetPayerFailed is returned if the payer of a payment cannot be recovered, the block is indexed again later
+var errGetPayerFailed = errors.New("cannot get payer")
+
+// WalletEvent is a change of a wallet in StochasticPayVRF.
+// StochasticPayVRF emits no logs, so the events are decoded from the successful transactions sent to it.
+type WalletEvent struct {
+	Type      string          `json:"type"`
+	Owner     gethcmn.Address `json:"owner"`
+	Token     gethcmn.Address `json:"token"`
+	Amount    *uint256.Int    `json:"amount"` // deposited, withdrawn or paid in total
+	Payees    []WalletPayee   `json:"payees,omitempty"`
+	Height    int64           `json:"height"`
+	TxIndex   int64           `json:"txIndex"`
+	TxHash    gethcmn.Hash    `json:"txHash"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+type WalletPayee struct {
+	Addr   gethcmn.Address `json:"addr"`
+	Amount *uint256.Int    `json:"amount"`
+}
+
+// ----------------------------------------------------------------
+
+// walletLedger keeps the events of each wallet, it is rolled back with the followed blocks.
+// It records the height up to which the blocks are indexed, the ones after it are indexed again after a failure.
+type walletLedger struct {
+	db *badger.DB
+}
+
+const (
+	walletEventKeyPrefix  = byte('w') // w || owner || token || height || txIndex => WalletEvent in JSON
+	walletHeightKeyPrefix = byte('b') // b || height || txIndex || owner => the key of the event
+	walletIndexedKey      = byte('i') // i => the height up to which the blocks are indexed
+)
+
+// openWalletLedger opens the ledger, the blocks up to `followedHeight` are not indexed if it is created
+func openWalletLedger(dir string, followedHeight int64) (*walletLedger, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, err
+	}
+	l := &walletLedger{db: db}
+	err = db.Update(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte{walletIndexedKey})
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return setIndexedHeight(txn, followedHeight)
+		}
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *walletLedger) close() error {
+	return l.db.Close()
+}
+
+func walletKeyPrefix(owner, token gethcmn.Address) []byte {
+	key := make([]byte, 0, 1+20+20+8+8)
+	key = append(key, walletEventKeyPrefix)
+	key = append(key, owner.Bytes()...)
+	return append(key, token.Bytes()...)
+}
+
+func walletEventKey(e *WalletEvent) []byte {
+	key := walletKeyPrefix(e.Owner, e.Token)
+	key = appendUint64(key, uint64(e.Height))
+	return appendUint64(key, uint64(e.TxIndex))
+}
+
+func walletHeightKey(e *WalletEvent) []byte {
+	key := make([]byte, 0, 1+8+8+20)
+	key = append(key, walletHeightKeyPrefix)
+	key = appendUint64(key, uint64(e.Height))
+	key = appendUint64(key, uint64(e.TxIndex))
+	return append(key, e.Owner.Bytes()...)
+}
+
+func appendUint64(bz []byte, n uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], n)
+	return append(bz, buf[:]...)
+}
+
+func setIndexedHeight(txn *badger.Txn, height int64) error {
+	return txn.Set([]byte{walletIndexedKey}, appendUint64(nil, uint64(height)))
+}
+
+func getIndexedHeight(txn *badger.Txn) (int64, error) {
+	item, err := txn.Get([]byte{walletIndexedKey})
+	if err != nil {
+		return 0, err
+	}
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return 0, err
+	}
+	if len(value) != 8 {
+		return 0, errors.New("invalid indexed height of wallet ledger")
+	}
+	return int64(binary.BigEndian.Uint64(value)), nil
+}
+
+// indexedHeight returns the height up to which the blocks are indexed
+func (l *walletLedger) indexedHeight() (height int64, err error) {
+	err = l.db.View(func(txn *badger.Txn) error {
+		height, err = getIndexedHeight(txn)
+		return err
+	})
+	return
+}
+
+// add saves the events of the block at `height`, and marks it indexed
+func (l *walletLedger) add(height int64, events []*WalletEvent) error {
+	return l.db.Update(func(txn *badger.Txn) error {
+		if err := setIndexedHeight(txn, height); err != nil {
+			return err
+		}
+		for _, e := range events {
+			value, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			if err = txn.Set(walletEventKey(e), value); err != nil {
+				return err
+			}
+			if err = txn.Set(walletHeightKey(e), walletEventKey(e)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// removeAfter drops the events of the orphaned blocks above `height`
+func (l *walletLedger) removeAfter(height int64) error {
+	return l.db.Update(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte{walletHeightKeyPrefix}
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		var keys [][]byte
+		start := appendUint64([]byte{walletHeightKeyPrefix}, uint64(height+1))
+		for iter.Seek(start); iter.Valid(); iter.Next() {
+			eventKey, err := iter.Item().ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, iter.Item().KeyCopy(nil), eventKey)
+		}
+		for _, key := range keys {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+		indexedHeight, err := getIndexedHeight(txn)
+		if err != nil || indexedHeight <= height {
+			return err
+		}
+		return setIndexedHeight(txn, height)
+	})
+}
+
+// query returns the events of a wallet in [startHeight, endHeight], in the order of height
+func (l *walletLedger) query(owner, token gethcmn.Address, startHeight, endHeight int64, limit int) ([]*WalletEvent, error) {
+	if limit <= 0 || limit > MaxWalletEventsPerQuery {
+		limit = MaxWalletEventsPerQuery
+	}
+	prefix := walletKeyPrefix(owner, token)
+	var events []*WalletEvent
+	err := l.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = prefix
+		iter := txn.NewIterator(opts)
+		defer iter.Close()
+
+		start := appendUint64(append([]byte{}, prefix...), uint64(startHeight))
+		for iter.Seek(start); iter.Valid() && len(events) < limit; iter.Next() {
+			var e WalletEvent
+			if err := iter.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &e)
+			}); err != nil {
+				return err
+			}
+			if e.Height > endHeight {
+				break
+			}
+			events = append(events, &e)
+		}
+		return nil
+	})
+	return events, err
+}
+
+// ----------------------------------------------------------------
+
+// payerRecoverer recovers the payer of a payment with the getPayer_ab and getPayer_sr view functions
+type payerRecoverer func(msg geth.CallMsg) ([]byte, error)
+
+// decodeWalletEvents decodes the wallet events from the successful transactions to StochasticPayVRF in a block.
+// A transaction which cannot be decoded is logged and skipped, so that it does not drop the other events of the block.
+// Only the transactions sent to StochasticPayVRF directly are decoded, the deposits and payments made by other
+// contracts calling it are missed, since it emits no logs and the internal calls are not kept in the history.
+func decodeWalletEvents(blk *modbtypes.Block, timestamp int64, getPayer payerRecoverer, logger tmlog.Logger) ([]*WalletEvent, error) {
+	stochasticPayABI, err := contract.StochasticPayVRFMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*WalletEvent
+	for _, rawTx := range blk.TxList {
+		if gethcmn.Address(rawTx.DstAddr) != contract.StochasticPayVRFAddress {
+			continue
+		}
+		e, err := decodeWalletEvent(stochasticPayABI, &rawTx, getPayer)
+		if errors.Is(err, errGetPayerFailed) {
+			return nil, err
+		}
+		if err != nil {
+			logger.Error("skipping the wallet tx which cannot be decoded", "height", blk.Height,
+				"tx", gethcmn.Hash(rawTx.HashId).Hex(), "error", err)
+			continue
+		}
+		if e == nil {
+			continue
+		}
+		e.Height = blk.Height
+		e.Timestamp = timestamp
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// decodeWalletEvent returns nil if the transaction does not change a wallet
+func decodeWalletEvent(stochasticPayABI *abi.ABI, rawTx *modbtypes.Tx, getPayer payerRecoverer) (*WalletEvent, error) {
+	var tx moevmtypes.Transaction
+	if _, err := tx.UnmarshalMsg(rawTx.Content); err != nil {
+		return nil, err
+	}
+	if tx.Status != gethtypes.ReceiptStatusSuccessful || len(tx.Input) < 4 {
+		return nil, nil
+	}
+	method, err := stochasticPayABI.MethodById(tx.Input[:4])
+	if err != nil {
+		return nil, nil // not a method of StochasticPayVRF
+	}
+	args, err := method.Inputs.Unpack(tx.Input[4:])
+	if err != nil {
+		return nil, fmt.Errorf("cannot unpack %s: %w", method.Name, err)
+	}
+
+	var e *WalletEvent
+	switch method.Name {
+	case "deposit":
+		e = decodeDeposit(&tx, args)
+	case "withdraw":
+		e = decodeWithdrawal(&tx, args)
+	case "payToAB":
+		e, err = decodePayToAB(stochasticPayABI, args, getPayer)
+	case "payToSingleReciever":
+		e, err = decodePayToSingleReceiver(stochasticPayABI, args, getPayer)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %w", method.Name, err)
+	}
+	e.TxIndex = tx.TransactionIndex
+	e.TxHash = tx.Hash
+	return e, nil
+}
+
+// splitAddressAmount splits sep20Contract_amount or payeeAddrA_amountA: address(160) || amount(96)
+func splitAddressAmount(packed *big.Int) (gethcmn.Address, *uint256.Int) {
+	bz := gethcmn.LeftPadBytes(packed.Bytes(), 32)
+	return gethcmn.BytesToAddress(bz[:20]), uint256.NewInt(0).SetBytes(bz[20:])
+}
+
+func decodeDeposit(tx *moevmtypes.Transaction, args []interface{}) *WalletEvent {
+	token, amount := splitAddressAmount(args[1].(*big.Int))
+	// SBCH is deposited with the value of the transaction
+	if token == contract.SBCHTokenAddress && amount.IsZero() {
+		amount = uint256.NewInt(0).SetBytes(tx.Value[:])
+	}
+	return &WalletEvent{Type: WalletEventDeposit, Owner: args[0].(gethcmn.Address), Token: token, Amount: amount}
+}
+
+func decodeWithdrawal(tx *moevmtypes.Transaction, args []interface{}) *WalletEvent {
+	token, amount := splitAddressAmount(args[0].(*big.Int))
+	return &WalletEvent{Type: WalletEventWithdrawal, Owner: tx.From, Token: token, Amount: amount}
+}
+
+func decodePayToAB(stochasticPayABI *abi.ABI, args []interface{}, getPayer payerRecoverer) (*WalletEvent, error) {
+	params := *abi.ConvertType(args[2], new(contract.StochasticPayVRFParams)).(*contract.StochasticPayVRFParams)
+
+	token, _ := splitAddressAmount(params.Sep20ContractDueTime64Prob32)
+	payeeA, amountA := splitAddressAmount(params.PayeeAddrAAmountA)
+	amountB := new(big.Int).Rsh(params.AmountBV, 8) // amountB || v
+	v := uint8(params.AmountBV.Uint64())
+	// B is the validator with the VRF public key
+	pkXY := append(gethcmn.LeftPadBytes(params.PkX.Bytes(), 32), gethcmn.LeftPadBytes(params.PkY.Bytes(), 32)...)
+	payeeB := gethcmn.BytesToAddress(gethcrypto.Keccak256(pkXY)[12:])
+
+	data, err := stochasticPayABI.Pack("getPayer_ab", params.PayerSalt, params.PkHashRoot,
+		params.Sep20ContractDueTime64Prob32, params.SeenNonces, params.PayeeAddrAAmountA, amountB, v, params.R, params.S)
+	if err != nil {
+		return nil, err
+	}
+	payer, err := callGetPayer(stochasticPayABI, "getPayer_ab", data, getPayer)
+	if err != nil {
+		return nil, err
+	}
+
+	amountB256, _ := uint256.FromBig(amountB)
+	return &WalletEvent{
+		Type:   WalletEventPayToAB,
+		Owner:  payer,
+		Token:  token,
+		Amount: uint256.NewInt(0).Add(amountA, amountB256),
+		Payees: []WalletPayee{{Addr: payeeA, Amount: amountA}, {Addr: payeeB, Amount: amountB256}},
+	}, nil
+}
+
+func decodePayToSingleReceiver(stochasticPayABI *abi.ABI, args []interface{}, getPayer payerRecoverer) (*WalletEvent, error) {
+	params := *abi.ConvertType(args[1], new(contract.StochasticPayVRFParamsSr)).(*contract.StochasticPayVRFParamsSr)
+
+	token, amount := splitAddressAmount(params.Sep20ContractAmount)
+	payee, _ := splitAddressAmount(params.PayeeAddrDueTime64Prob32)
+
+	data, err := stochasticPayABI.Pack("getPayer_sr", params.PayerSaltPk0V, params.PkTail,
+		params.PayeeAddrDueTime64Prob32, params.SeenNonces, params.Sep20ContractAmount, params.R, params.S)
+	if err != nil {
+		return nil, err
+	}
+	payer, err := callGetPayer(stochasticPayABI, "getPayer_sr", data, getPayer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WalletEvent{
+		Type:   WalletEventPayToSingleReceiver,
+		Owner:  payer,
+		Token:  token,
+		Amount: amount,
+		Payees: []WalletPayee{{Addr: payee, Amount: amount}},
+	}, nil
+}
+
+func callGetPayer(stochasticPayABI *abi.ABI, method string, data []byte, getPayer payerRecoverer) (gethcmn.Address, error) {
+	to := contract.StochasticPayVRFAddress
+	outData, err := getPayer(geth.CallMsg{To: &to, Data: data})
+	if err != nil {
+		return gethcmn.Address{}, fmt.Errorf("%w: %s", errGetPayerFailed, err)
+	}
+	out, err := stochasticPayABI.Unpack(method, outData)
+	if err != nil {
+		return gethcmn.Address{}, err
+	}
+	if len(out) != 1 {
+		return gethcmn.Address{}, errors.New("invalid output of " + method)
+	}
+	return out[0].(gethcmn.Address), nil
+}
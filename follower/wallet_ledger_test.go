@@ -0,0 +1,193 @@
+package follower
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	geth "github.com/ethereum/go-ethereum"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	modbtypes "github.com/smartbch/moeingdb/types"
+	moevmtypes "github.com/smartbch/moeingevm/types"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/smartbch/ganychain/contract"
+)
+
+var (
+	testOwner = gethcmn.HexToAddress("0x06C14ED469FB93545cbF071b593D8f90194Ede62")
+	testPayee = gethcmn.HexToAddress("0x423403784Ca5bD868731d604Ad097f126B36CAe2")
+	testToken = gethcmn.HexToAddress("0x265bD28d79400D55a1665707Fa14A72978FA6043")
+)
+
+// packAddressAmount packs address(160) || amount(96)
+func packAddressAmount(addr gethcmn.Address, amount int64) *big.Int {
+	packed := new(big.Int).Lsh(new(big.Int).SetBytes(addr.Bytes()), 96)
+	return packed.Or(packed, big.NewInt(amount))
+}
+
+func newTestTx(t *testing.T, index int64, from, to gethcmn.Address, status uint64, method string, args ...interface{}) modbtypes.Tx {
+	stochasticPayABI, err := contract.StochasticPayVRFMetaData.GetAbi()
+	require.NoError(t, err)
+	input, err := stochasticPayABI.Pack(method, args...)
+	require.NoError(t, err)
+
+	tx := moevmtypes.Transaction{
+		Hash:             gethcrypto.Keccak256Hash(input, []byte{byte(index)}),
+		TransactionIndex: index,
+		From:             from,
+		To:               to,
+		Input:            input,
+		Status:           status,
+	}
+	content, err := tx.MarshalMsg(nil)
+	require.NoError(t, err)
+	return modbtypes.Tx{HashId: tx.Hash, SrcAddr: from, DstAddr: to, Content: content}
+}
+
+func TestDecodeWalletEvents(t *testing.T) {
+	validatorKey, err := gethcrypto.GenerateKey()
+	require.NoError(t, err)
+	validator := gethcrypto.PubkeyToAddress(validatorKey.PublicKey)
+	success, failed := gethtypes.ReceiptStatusSuccessful, gethtypes.ReceiptStatusFailed
+	spAddr := contract.StochasticPayVRFAddress
+
+	abParams := contract.StochasticPayVRFParams{
+		PayerSalt:                    big.NewInt(1),
+		PkX:                          validatorKey.PublicKey.X,
+		PkY:                          validatorKey.PublicKey.Y,
+		Sep20ContractDueTime64Prob32: packAddressAmount(testToken, 1<<32|1),
+		SeenNonces:                   big.NewInt(0),
+		PayeeAddrAAmountA:            packAddressAmount(testPayee, 30),
+		AmountBV:                     big.NewInt(20<<8 | 27),
+	}
+	srParams := contract.StochasticPayVRFParamsSr{
+		PayerSaltPk0V:            big.NewInt(28),
+		PkTail:                   big.NewInt(0),
+		PayeeAddrDueTime64Prob32: packAddressAmount(testPayee, 1<<32|1),
+		SeenNonces:               big.NewInt(0),
+		Sep20ContractAmount:      packAddressAmount(testToken, 50),
+	}
+	blk := &modbtypes.Block{
+		Height: 100,
+		TxList: []modbtypes.Tx{
+			newTestTx(t, 0, testOwner, spAddr, success, "deposit", testOwner, packAddressAmount(testToken, 100)),
+			newTestTx(t, 1, testOwner, spAddr, failed, "withdraw", packAddressAmount(testToken, 1000)),
+			newTestTx(t, 2, testOwner, testToken, success, "withdraw", packAddressAmount(testToken, 1000)),
+			newTestTx(t, 3, testOwner, spAddr, success, "withdraw", packAddressAmount(testToken, 10)),
+			newTestTx(t, 4, validator, spAddr, success, "payToAB", [][32]byte{}, []byte{1}, abParams),
+			newTestTx(t, 5, testPayee, spAddr, success, "payToSingleReciever", []byte{1}, srParams),
+		},
+	}
+	// an undecodable tx is skipped without dropping the others
+	undecodable := newTestTx(t, 6, testOwner, spAddr, success, "deposit", testOwner, packAddressAmount(testToken, 100))
+	var tx moevmtypes.Transaction
+	_, err = tx.UnmarshalMsg(undecodable.Content)
+	require.NoError(t, err)
+	tx.Input = tx.Input[:10]
+	undecodable.Content, err = tx.MarshalMsg(nil)
+	require.NoError(t, err)
+	blk.TxList = append(blk.TxList[:4], undecodable, blk.TxList[4], blk.TxList[5])
+
+	stochasticPayABI, err := contract.StochasticPayVRFMetaData.GetAbi()
+	require.NoError(t, err)
+	var getPayerCalls []string
+	events, err := decodeWalletEvents(blk, 1650000000, func(msg geth.CallMsg) ([]byte, error) {
+		require.Equal(t, spAddr, *msg.To)
+		method, err := stochasticPayABI.MethodById(msg.Data[:4])
+		require.NoError(t, err)
+		getPayerCalls = append(getPayerCalls, method.Name)
+		return stochasticPayABI.Methods[method.Name].Outputs.Pack(testOwner)
+	}, tmlog.NewNopLogger())
+	require.NoError(t, err)
+	require.Equal(t, []string{"getPayer_ab", "getPayer_sr"}, getPayerCalls)
+	require.Len(t, events, 4)
+
+	for _, e := range events {
+		require.Equal(t, testOwner, e.Owner)
+		require.Equal(t, testToken, e.Token)
+		require.EqualValues(t, 100, e.Height)
+		require.EqualValues(t, 1650000000, e.Timestamp)
+	}
+	require.Equal(t, WalletEventDeposit, events[0].Type)
+	require.EqualValues(t, 100, events[0].Amount.Uint64())
+	require.Equal(t, WalletEventWithdrawal, events[1].Type)
+	require.EqualValues(t, 3, events[1].TxIndex)
+	require.EqualValues(t, 10, events[1].Amount.Uint64())
+
+	require.Equal(t, WalletEventPayToAB, events[2].Type)
+	require.EqualValues(t, 50, events[2].Amount.Uint64())
+	require.Len(t, events[2].Payees, 2)
+	require.Equal(t, testPayee, events[2].Payees[0].Addr)
+	require.EqualValues(t, 30, events[2].Payees[0].Amount.Uint64())
+	require.Equal(t, validator, events[2].Payees[1].Addr)
+	require.EqualValues(t, 20, events[2].Payees[1].Amount.Uint64())
+
+	require.Equal(t, WalletEventPayToSingleReceiver, events[3].Type)
+	require.EqualValues(t, 50, events[3].Amount.Uint64())
+	require.Equal(t, []WalletPayee{{Addr: testPayee, Amount: events[3].Amount}}, events[3].Payees)
+
+	// the block is not indexed if the payer cannot be recovered
+	_, err = decodeWalletEvents(blk, 1650000000, func(msg geth.CallMsg) ([]byte, error) {
+		return nil, errors.New("state is busy")
+	}, tmlog.NewNopLogger())
+	require.ErrorIs(t, err, errGetPayerFailed)
+}
+
+func TestWalletLedger(t *testing.T) {
+	ledger, err := openWalletLedger(t.TempDir(), 50)
+	require.NoError(t, err)
+	defer ledger.close()
+	indexedHeight, err := ledger.indexedHeight()
+	require.NoError(t, err)
+	require.EqualValues(t, 50, indexedHeight)
+
+	newEvent := func(owner, token gethcmn.Address, height, txIndex int64) *WalletEvent {
+		return &WalletEvent{Type: WalletEventDeposit, Owner: owner, Token: token, Height: height, TxIndex: txIndex}
+	}
+	require.NoError(t, ledger.add(100, []*WalletEvent{
+		newEvent(testOwner, testToken, 100, 0),
+		newEvent(testOwner, testToken, 100, 3),
+		newEvent(testOwner, contract.SBCHTokenAddress, 100, 4),
+		newEvent(testPayee, testToken, 100, 5),
+	}))
+	require.NoError(t, ledger.add(200, []*WalletEvent{newEvent(testOwner, testToken, 200, 1)}))
+	require.NoError(t, ledger.add(300, []*WalletEvent{newEvent(testOwner, testToken, 300, 0)}))
+	indexedHeight, err = ledger.indexedHeight()
+	require.NoError(t, err)
+	require.EqualValues(t, 300, indexedHeight)
+
+	events, err := ledger.query(testOwner, testToken, 0, 1000, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+	require.EqualValues(t, 3, events[1].TxIndex)
+	require.EqualValues(t, 300, events[3].Height)
+
+	events, err = ledger.query(testOwner, testToken, 101, 299, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.EqualValues(t, 200, events[0].Height)
+
+	events, err = ledger.query(testOwner, testToken, 0, 1000, 2)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	events, err = ledger.query(testPayee, testToken, 0, 1000, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// the blocks above 150 are orphaned
+	require.NoError(t, ledger.removeAfter(150))
+	events, err = ledger.query(testOwner, testToken, 0, 1000, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	events, err = ledger.query(testOwner, contract.SBCHTokenAddress, 0, 1000, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	indexedHeight, err = ledger.indexedHeight()
+	require.NoError(t, err)
+	require.EqualValues(t, 150, indexedHeight)
+}
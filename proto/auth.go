@@ -8,6 +8,7 @@ import (
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	gethmath "github.com/ethereum/go-ethereum/common/math"
 	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
 
 	"github.com/smartbch/ganychain/utils/cryptoutils"
 	"github.com/smartbch/ganychain/utils/ugo"
@@ -48,13 +49,27 @@ func (x *AuthProof) GenerateAuthChallenge() *AuthChallenge {
 
 type AuthProofType int8
 
+// DynamicSetCaller runs the calls of the dynamic set proofs on the state of a chain,
+// so these proofs need no authenticator signature. The follower service implements it for smartBCH.
+type DynamicSetCaller interface {
+	GetChainId() *uint256.Int
+	// CallContract runs a read-only call, it returns the output and the timestamp of the block it runs on
+	CallContract(from, to gethcmn.Address, data []byte) ([]byte, int64, error)
+}
+
+// DynamicSetCallers finds the caller of a chain, it returns nil if the chain is unknown
+type DynamicSetCallers interface {
+	GetCaller(chainId *uint256.Int) DynamicSetCaller
+}
+
 const (
 	DynamicProofType           = AuthProofType(1)
 	StaticProofType            = AuthProofType(2)
 	StochasticPaymentProofType = AuthProofType(3)
 )
 
-func (x *AuthProof) CheckAuthProof(sp *StochasticPayment, b *Bulletin) bool {
+// CheckAuthProof checks the proofs against the conditions, callers can be nil if no chain can be called
+func (x *AuthProof) CheckAuthProof(sp *StochasticPayment, b *Bulletin, callers DynamicSetCallers) bool {
 	if len(x.OrOfAndOfConditions) != 3 {
 		return false
 	}
@@ -85,7 +100,7 @@ func (x *AuthProof) CheckAuthProof(sp *StochasticPayment, b *Bulletin) bool {
 
 		andOk := true
 		for _, conNum := range andOfCon.ConditionNumbers {
-			andOk = andOk && x.checkProof(conMap[conNum], conNum, sp, b)
+			andOk = andOk && x.checkProof(conMap[conNum], conNum, sp, b, callers)
 		}
 		orOk = orOk || andOk
 	}
@@ -93,10 +108,10 @@ func (x *AuthProof) CheckAuthProof(sp *StochasticPayment, b *Bulletin) bool {
 	return orOk
 }
 
-func (x *AuthProof) checkProof(proofType AuthProofType, conditionIndex int32, sp *StochasticPayment, b *Bulletin) bool {
+func (x *AuthProof) checkProof(proofType AuthProofType, conditionIndex int32, sp *StochasticPayment, b *Bulletin, callers DynamicSetCallers) bool {
 	switch proofType {
 	case DynamicProofType:
-		return x.checkDynamicProof(conditionIndex, b.From[:])
+		return x.checkDynamicProof(conditionIndex, b.From[:], callers)
 	case StaticProofType:
 		return x.checkStaticProof(conditionIndex, b.From[:])
 	case StochasticPaymentProofType:
@@ -106,7 +121,7 @@ func (x *AuthProof) checkProof(proofType AuthProofType, conditionIndex int32, sp
 	return false
 }
 
-func (x *AuthProof) checkDynamicProof(index int32, from []byte) bool {
+func (x *AuthProof) checkDynamicProof(index int32, from []byte, callers DynamicSetCallers) bool {
 	if len(x.DynamicSetProofList) == 0 && len(x.OrOfAndOfConditions[0].ConditionNumbers) == 0 {
 		return true
 	}
@@ -116,27 +131,44 @@ func (x *AuthProof) checkDynamicProof(index int32, from []byte) bool {
 		return false
 	}
 
-	ei := &ethCallInfo{
-		ChainId:   ugo.BytesToBig(x.DynamicSetProofList[i].ChainId),
-		Timestamp: new(big.Int).SetInt64(x.DynamicSetProofList[i].Timestamp),
-		From:      gethcmn.BytesToAddress(from),
-		To:        gethcmn.BytesToAddress(x.DynamicSetProofList[i].TargetContract),
-		OutData:   x.DynamicSetProofList[i].OutData,
+	dsp := x.DynamicSetProofList[i]
+	ei := dsp.ethCallInfo(from)
+	if chainId, overflow := uint256.FromBig(ei.ChainId); callers != nil && !overflow {
+		if caller := callers.GetCaller(chainId); caller != nil {
+			return checkDynamicProofByCall(caller, ei, dsp.MaxTimeDifference)
+		}
 	}
+	return checkDynamicProofBySig(ei, dsp)
+}
 
-	binary.BigEndian.PutUint32(ei.FunctionSelector[:], x.DynamicSetProofList[i].FunctionSelector)
-	eiBz := ei.ToBytes()
-	eiHash := gethcrypto.Keccak256(eiBz)
-
-	pubKey, err := gethcrypto.SigToPub(eiHash[:], x.DynamicSetProofList[i].AuthenticatorSignature)
+// checkDynamicProofByCall runs the call with `from` as the sender, the output must be the same as OutData,
+// and the followed state must be within MaxTimeDifference seconds from the timestamp of the proof.
+func checkDynamicProofByCall(caller DynamicSetCaller, ei *ethCallInfo, maxTimeDifference int64) bool {
+	outData, blockTime, err := caller.CallContract(ei.From, ei.To, ei.FunctionSelector[:])
 	if err != nil {
 		return false
 	}
 
-	if gethcrypto.PubkeyToAddress(*pubKey).Hex() != gethcmn.BytesToAddress(x.DynamicSetProofList[i].Authenticator).Hex() {
+	timeDifference := blockTime - ei.Timestamp.Int64()
+	if timeDifference < 0 {
+		timeDifference = -timeDifference
+	}
+	if timeDifference > maxTimeDifference {
 		return false
 	}
-	return false
+	return bytes.Equal(outData, ei.OutData)
+}
+
+// checkDynamicProofBySig checks the authenticator's signature on the call, for the chains which are not followed
+func checkDynamicProofBySig(ei *ethCallInfo, dsp *DynamicSetProof) bool {
+	eiHash := gethcrypto.Keccak256(ei.ToBytes())
+
+	pubKey, err := gethcrypto.SigToPub(eiHash[:], dsp.AuthenticatorSignature)
+	if err != nil {
+		return false
+	}
+
+	return gethcrypto.PubkeyToAddress(*pubKey) == gethcmn.BytesToAddress(dsp.Authenticator)
 }
 
 func (x *AuthProof) checkStaticProof(index int32, from []byte) bool {
@@ -174,6 +206,23 @@ func (x *AuthProof) checkStochasticPaymentCondition(index int32, sp *StochasticP
 
 // ----------------------------------------------------------------
 
+// EthCallInfoHash returns the hash signed by the authenticator, `from` is the sender of the call
+func (x *DynamicSetProof) EthCallInfoHash(from []byte) []byte {
+	return gethcrypto.Keccak256(x.ethCallInfo(from).ToBytes())
+}
+
+func (x *DynamicSetProof) ethCallInfo(from []byte) *ethCallInfo {
+	ei := &ethCallInfo{
+		ChainId:   ugo.BytesToBig(x.ChainId),
+		Timestamp: new(big.Int).SetInt64(x.Timestamp),
+		From:      gethcmn.BytesToAddress(from),
+		To:        gethcmn.BytesToAddress(x.TargetContract),
+		OutData:   x.OutData,
+	}
+	binary.BigEndian.PutUint32(ei.FunctionSelector[:], x.FunctionSelector)
+	return ei
+}
+
 type ethCallInfo struct {
 	ChainId          *big.Int
 	Timestamp        *big.Int
@@ -15,16 +15,36 @@ var (
 	PayerSaltTemplate = "payerSalt"
 )
 
+// VRFPubkeyLength is the length of a compressed secp256k1 public key
+const VRFPubkeyLength = 33
+
 func (x *StochasticPayment) IsValid() bool {
-	return len(x.ValidatorPubkeyHashRoot) == 32 &&
-		x.DueTime > 0 &&
+	if x.IsSingleReceiver() {
+		if len(x.ValidatorPubkey) != VRFPubkeyLength ||
+			len(x.ValidatorPubkeyHashRoot) != 0 ||
+			len(x.AmountToValidator) != 0 ||
+			len(x.Payee) != gethcmn.AddressLength {
+			return false
+		}
+	} else if len(x.ValidatorPubkeyHashRoot) != 32 {
+		return false
+	}
+	return x.DueTime > 0 &&
 		x.Probability > 0 &&
 		(x.Payee == nil || len(x.Payee) == gethcmn.AddressLength) &&
+		(len(x.Sep20Contract) == 0 || len(x.Sep20Contract) == gethcmn.AddressLength) &&
 		len(x.AmountToPayee) <= 12 &&
 		len(x.AmountToValidator) <= 12 &&
 		len(x.Signature) == 65
 
 }
+
+// IsSingleReceiver returns true if the payment carries the VRF public key of a single receiver, then AmountToPayee
+// is paid to the payee with payToSingleReciever, and there is neither a validator leg nor a ValidatorPubkeyHashRoot.
+// Otherwise, it is paid to the payee and the validator with payToAB.
+func (x *StochasticPayment) IsSingleReceiver() bool {
+	return len(x.ValidatorPubkey) != 0
+}
 func (x *StochasticPayment) GetRSV() ([32]byte, [32]byte, byte) {
 	var r [32]byte
 	var s [32]byte
@@ -35,7 +55,7 @@ func (x *StochasticPayment) GetRSV() ([32]byte, [32]byte, byte) {
 
 func (x *StochasticPayment) GenEIP712MsgForSR(tokenAddr gethcmn.Address) eip712types.TypedDataMessage {
 	payerSalt := crypto.Keccak256([]byte(PayerSaltTemplate))
-	payerSaltPk0, pkTail := concatPayerSaltPublicKey(payerSalt, x.ValidatorPubkeyHashRoot)
+	payerSaltPk0, pkTail := concatPayerSaltPublicKey(payerSalt, x.ValidatorPubkey)
 
 	return eip712types.TypedDataMessage{
 		"payerSalt_pk0": hexutil.Encode(payerSaltPk0),
@@ -63,15 +83,16 @@ func (x *StochasticPayment) GenEIP712MsgForAB(tokenAddr gethcmn.Address) eip712t
 
 // ----------------------------------------------------------------
 
-// return payerSalt_pk0, pkTail
-func concatPayerSaltPublicKey(payerSalt, pkHash []byte) ([]byte, []byte) {
+// return payerSalt_pk0, pkTail, the prefix of the compressed public key is packed into the
+// lowest byte of payerSalt_pk0, and pkTail is its X coordinate
+func concatPayerSaltPublicKey(payerSalt, pubkey []byte) ([]byte, []byte) {
 	var result [31]byte
 	copy(result[:30], payerSalt[:30])
-	result[30] = pkHash[0]
+	result[30] = pubkey[0]
 
 	// set the highest bit to enable payment
 	result[0] = 0x80 | result[0]
-	return result[:], pkHash[1:]
+	return result[:], pubkey[1:]
 }
 
 func concatAddrDueTime64Prob32(payee [20]byte, dueTime int64, prob32 uint32) []byte {
@@ -8,7 +8,8 @@ import (
 )
 
 const (
-	namespaceGany = "gany"
+	namespaceGany          = "gany"
+	namespaceAuthenticator = "authenticator"
 
 	apiVersion = "1.0"
 )
@@ -19,7 +20,7 @@ func GetAPIs(backend backend.BackendService, logger tmlog.Logger) []gethrpc.API
 	logger = logger.With("module", "json-rpc")
 	_ganyAPI := newGanyAPI(backend, logger)
 
-	return []gethrpc.API{
+	apis := []gethrpc.API{
 		{
 			Namespace: namespaceGany,
 			Version:   apiVersion,
@@ -27,4 +28,14 @@ func GetAPIs(backend backend.BackendService, logger tmlog.Logger) []gethrpc.API
 			Public:    true,
 		},
 	}
+
+	if _, ok := backend.GetAuthenticatorAddress(); ok {
+		apis = append(apis, gethrpc.API{
+			Namespace: namespaceAuthenticator,
+			Version:   apiVersion,
+			Service:   newAuthenticatorAPI(backend, logger),
+			Public:    true,
+		})
+	}
+	return apis
 }
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/protobuf/proto"
+	"github.com/holiman/uint256"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+
+	"github.com/smartbch/ganychain/backend"
+)
+
+var _ PublicAuthenticatorAPI = (*authenticatorAPI)(nil)
+
+// PublicAuthenticatorAPI is served only if the node has an authenticator key
+type PublicAuthenticatorAPI interface {
+	Address() gethcmn.Address
+	SignDynamicSetProof(ctx context.Context, chainId hexutil.Uint64, from, targetContract gethcmn.Address,
+		functionSelector hexutil.Bytes, maxTimeDifference int64) (hexutil.Bytes, error)
+}
+
+type authenticatorAPI struct {
+	backend backend.BackendService
+	logger  tmlog.Logger
+}
+
+func newAuthenticatorAPI(backend backend.BackendService, logger tmlog.Logger) PublicAuthenticatorAPI {
+	return &authenticatorAPI{
+		backend: backend,
+		logger:  logger,
+	}
+}
+
+func (a *authenticatorAPI) Address() gethcmn.Address {
+	a.logger.Debug("authenticator_address")
+
+	addr, _ := a.backend.GetAuthenticatorAddress()
+	return addr
+}
+
+// SignDynamicSetProof returns the protobuf bytes of the signed pb.DynamicSetProof of the chain `chainId`,
+// which can be put into the AuthProof of a bulletin sent by `from`. It shares the IP rate limit with
+// gany_putBulletin, since each call runs a contract call.
+func (a *authenticatorAPI) SignDynamicSetProof(ctx context.Context, chainId hexutil.Uint64, from, targetContract gethcmn.Address,
+	functionSelector hexutil.Bytes, maxTimeDifference int64) (hexutil.Bytes, error) {
+
+	a.logger.Debug("authenticator_signDynamicSetProof")
+
+	if err := a.backend.CheckIPRateLimit(remoteIP(ctx)); err != nil {
+		return nil, err
+	}
+
+	if len(functionSelector) != 4 {
+		return nil, fmt.Errorf("function selector length %d != 4", len(functionSelector))
+	}
+
+	dsp, err := a.backend.SignDynamicSetProof(uint256.NewInt(uint64(chainId)), from, targetContract, binary.BigEndian.Uint32(functionSelector), maxTimeDifference)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(dsp)
+}
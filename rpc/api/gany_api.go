@@ -1,29 +1,134 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/big"
+	"net"
 	"strings"
 
+	geth "github.com/ethereum/go-ethereum"
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/golang/protobuf/proto"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 
 	"github.com/smartbch/ganychain/backend"
+	"github.com/smartbch/ganychain/contract"
+	"github.com/smartbch/ganychain/follower"
 	pb "github.com/smartbch/ganychain/proto"
+	"github.com/smartbch/ganychain/web3client"
 )
 
 var _ PublicGanyAPI = (*ganyAPI)(nil)
 
 type PublicGanyAPI interface {
 	ChainIds() []string
-	PutBulletin(tx hexutil.Bytes) (tmbytes.HexBytes, error)
+	PutBulletin(ctx context.Context, tx hexutil.Bytes) (tmbytes.HexBytes, error)
+	SimulateBulletin(ctx context.Context, tx hexutil.Bytes) (*backend.SimulationReport, error)
+	QuotePrice(bulletin hexutil.Bytes, prob32 *hexutil.Uint64, token *gethcmn.Address) (*PriceQuote, error)
+	GetAcceptedTokens() []*AcceptedToken
 	GetBulletin(ganyUrl string) (hexutil.Bytes, error)
 	QueryBulletins(typ pb.Bulletin_BulletinType, topicHash hexutil.Bytes, start, end int64, snListBz []hexutil.Bytes) ([]hexutil.Bytes, error)
+	QueryBulletinsByAuthor(from gethcmn.Address, start, end int64) (*FanOutQueryResult, error)
+	QueryBulletinsByTime(types []pb.Bulletin_BulletinType, start, end int64) (*FanOutQueryResult, error)
+	SearchBulletins(keyword string, start, end int64) (*FanOutQueryResult, error)
 	GetDelegatedAddr(mainAddr gethcmn.Address) (gethcmn.Address, error)
 	LoadWalletInStochasticPay(tokenAddr, ownerAddr gethcmn.Address) ([]hexutil.Bytes, error)
+	GetWalletEvents(ownerAddr, tokenAddr gethcmn.Address, start, end int64) ([]*follower.WalletEvent, error)
 	GetValidatorPubKeyList() ([]hexutil.Bytes, error)
+	GetValidators() []*Validator
+	GetValidatorSetAt(height hexutil.Uint64) (*ValidatorSet, error)
+	GetValidatorSetByRoot(root hexutil.Bytes) (*ValidatorSet, error)
+	GetValidatorSetByElectedTime(electedTime hexutil.Uint64) (*ValidatorSet, error)
+	GetBulletinCacheStats() backend.BulletinCacheStats
+	SyncStatus() follower.SyncStatus
+	GetUpstreamStats() []web3client.EndpointStats
+	Call(ctx context.Context, args CallArgs, blockNr *gethrpc.BlockNumber) (hexutil.Bytes, error)
+}
+
+type FanOutQueryResult struct {
+	Bulletins    []hexutil.Bytes        `json:"bulletins"`
+	Partial      bool                   `json:"partial"`
+	FailedShards []backend.ShardFailure `json:"failedShards,omitempty"`
+}
+
+type PriceQuote struct {
+	BaseFee      *hexutil.Big   `json:"baseFee"`
+	ContentFee   *hexutil.Big   `json:"contentFee"`
+	TTLFee       *hexutil.Big   `json:"ttlFee"`
+	MinPrice     *hexutil.Big   `json:"minPrice"` // the minimum expected value of the payment to the validator
+	ContentBytes hexutil.Uint64 `json:"contentBytes"`
+	TTLHours     hexutil.Uint64 `json:"ttlHours"`
+
+	// the fees are in the smallest unit of this token
+	Token    gethcmn.Address `json:"token"`
+	Symbol   string          `json:"symbol"`
+	Decimals hexutil.Uint64  `json:"decimals"`
+
+	// the minimum AmountToValidator with the given probability
+	MinAmountToValidator *hexutil.Big `json:"minAmountToValidator,omitempty"`
+}
+
+type AcceptedToken struct {
+	Address  gethcmn.Address `json:"address"`
+	Symbol   string          `json:"symbol"`
+	Decimals hexutil.Uint64  `json:"decimals"`
+}
+
+type Validator struct {
+	Address        gethcmn.Address `json:"address"`
+	Pubkey         hexutil.Bytes   `json:"pubkey"` // compressed
+	RpcUrl         string          `json:"rpcUrl"` // ip:port of the gateway
+	Intro          string          `json:"intro"`
+	TotalStakedAmt *hexutil.Big    `json:"totalStakedAmt"`
+	SelfStakedAmt  *hexutil.Big    `json:"selfStakedAmt"`
+	ElectedTime    *hexutil.Big    `json:"electedTime"`
+	OldElectedTime *hexutil.Big    `json:"oldElectedTime"`
+	Elected        bool            `json:"elected"`
+}
+
+type ValidatorSet struct {
+	ElectedTime hexutil.Uint64 `json:"electedTime"`
+	Height      hexutil.Uint64 `json:"height"`
+	Root        hexutil.Bytes  `json:"root"`
+	Validators  []*Validator   `json:"validators"`
+}
+
+// CallArgs are the arguments of gany_call, like those of eth_call
+type CallArgs struct {
+	From     *gethcmn.Address `json:"from"`
+	To       *gethcmn.Address `json:"to"`
+	Gas      *hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big     `json:"gasPrice"`
+	Value    *hexutil.Big     `json:"value"`
+	Data     *hexutil.Bytes   `json:"data"`
+	Input    *hexutil.Bytes   `json:"input"` // the same as data
+}
+
+func (args *CallArgs) toCallMsg() geth.CallMsg {
+	msg := geth.CallMsg{To: args.To}
+	if args.From != nil {
+		msg.From = *args.From
+	}
+	if args.Gas != nil {
+		msg.Gas = uint64(*args.Gas)
+	}
+	if args.GasPrice != nil {
+		msg.GasPrice = args.GasPrice.ToInt()
+	}
+	if args.Value != nil {
+		msg.Value = args.Value.ToInt()
+	}
+	if args.Input != nil {
+		msg.Data = *args.Input
+	} else if args.Data != nil {
+		msg.Data = *args.Data
+	}
+	return msg
 }
 
 type ganyAPI struct {
@@ -43,9 +148,13 @@ func (g *ganyAPI) ChainIds() []string {
 	return g.backend.GetAllChainIds()
 }
 
-func (g *ganyAPI) PutBulletin(tx hexutil.Bytes) (tmbytes.HexBytes, error) {
+func (g *ganyAPI) PutBulletin(ctx context.Context, tx hexutil.Bytes) (tmbytes.HexBytes, error) {
 	g.logger.Debug("gany_putBulletin")
 
+	if err := g.backend.CheckIPRateLimit(remoteIP(ctx)); err != nil {
+		return nil, err
+	}
+
 	hash, err := g.backend.PutBulletin(pb.GanyTx(tx))
 	if err != nil {
 		return nil, err
@@ -53,6 +162,77 @@ func (g *ganyAPI) PutBulletin(tx hexutil.Bytes) (tmbytes.HexBytes, error) {
 	return hash, nil
 }
 
+// SimulateBulletin shares the IP rate limit with PutBulletin, since it runs the same checks
+func (g *ganyAPI) SimulateBulletin(ctx context.Context, tx hexutil.Bytes) (*backend.SimulationReport, error) {
+	g.logger.Debug("gany_simulateBulletin")
+
+	if err := g.backend.CheckIPRateLimit(remoteIP(ctx)); err != nil {
+		return nil, err
+	}
+	return g.backend.SimulateBulletin(pb.GanyTx(tx)), nil
+}
+
+// QuotePrice prices a bulletin before it is signed, `bulletin` is the protobuf bytes of pb.Bulletin.
+// The price is in SBCH if `token` is not given.
+func (g *ganyAPI) QuotePrice(bulletin hexutil.Bytes, prob32 *hexutil.Uint64, token *gethcmn.Address) (*PriceQuote, error) {
+	g.logger.Debug("gany_quotePrice")
+
+	var b pb.Bulletin
+	if err := proto.Unmarshal(bulletin, &b); err != nil {
+		return nil, err
+	}
+	if !b.IsValid() {
+		return nil, pb.ErrInvalidBulletinFields
+	}
+
+	tokenAddr := contract.SBCHTokenAddress
+	if token != nil {
+		tokenAddr = *token
+	}
+	q, acceptedToken, err := g.backend.QuotePrice(&b, tokenAddr)
+	if err != nil {
+		return nil, err
+	}
+	quote := &PriceQuote{
+		BaseFee:      (*hexutil.Big)(q.BaseFee.ToBig()),
+		ContentFee:   (*hexutil.Big)(q.ContentFee.ToBig()),
+		TTLFee:       (*hexutil.Big)(q.TTLFee.ToBig()),
+		MinPrice:     (*hexutil.Big)(q.MinPrice.ToBig()),
+		ContentBytes: hexutil.Uint64(q.ContentBytes),
+		TTLHours:     hexutil.Uint64(q.TTLHours),
+		Token:        acceptedToken.Address,
+		Symbol:       acceptedToken.Symbol,
+		Decimals:     hexutil.Uint64(acceptedToken.Decimals),
+	}
+
+	if prob32 != nil {
+		if uint64(*prob32) > math.MaxUint32 {
+			return nil, fmt.Errorf("prob32 %d overflows uint32", *prob32)
+		}
+		// ceil(minPrice * 2^32 / (prob32 + 1))
+		amount := new(big.Int).Lsh(q.MinPrice.ToBig(), 32)
+		divisor := new(big.Int).SetUint64(uint64(*prob32) + 1)
+		amount.Add(amount, new(big.Int).Sub(divisor, big.NewInt(1)))
+		quote.MinAmountToValidator = (*hexutil.Big)(amount.Div(amount, divisor))
+	}
+	return quote, nil
+}
+
+func (g *ganyAPI) GetAcceptedTokens() []*AcceptedToken {
+	g.logger.Debug("gany_getAcceptedTokens")
+
+	tokens := g.backend.GetAcceptedTokens()
+	result := make([]*AcceptedToken, len(tokens))
+	for i, token := range tokens {
+		result[i] = &AcceptedToken{
+			Address:  token.Address,
+			Symbol:   token.Symbol,
+			Decimals: hexutil.Uint64(token.Decimals),
+		}
+	}
+	return result
+}
+
 func (g *ganyAPI) GetBulletin(ganyUrl string) (hexutil.Bytes, error) {
 	g.logger.Debug("gany_getBulletin")
 
@@ -116,6 +296,62 @@ func (g *ganyAPI) QueryBulletins(typ pb.Bulletin_BulletinType, topicHash hexutil
 	return results, nil
 }
 
+func (g *ganyAPI) QueryBulletinsByAuthor(from gethcmn.Address, start, end int64) (*FanOutQueryResult, error) {
+	g.logger.Debug("gany_queryBulletinsByAuthor")
+
+	result, err := g.backend.QueryBulletinsByAuthor(from.Bytes(), start, end)
+	if err != nil {
+		return nil, err
+	}
+	return toFanOutQueryResult(result)
+}
+
+func (g *ganyAPI) QueryBulletinsByTime(types []pb.Bulletin_BulletinType, start, end int64) (*FanOutQueryResult, error) {
+	g.logger.Debug("gany_queryBulletinsByTime")
+
+	result, err := g.backend.QueryBulletinsByTime(types, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return toFanOutQueryResult(result)
+}
+
+func (g *ganyAPI) SearchBulletins(keyword string, start, end int64) (*FanOutQueryResult, error) {
+	g.logger.Debug("gany_searchBulletins")
+
+	result, err := g.backend.SearchBulletins([]byte(keyword), start, end)
+	if err != nil {
+		return nil, err
+	}
+	return toFanOutQueryResult(result)
+}
+
+func toFanOutQueryResult(result *backend.FanOutResult) (*FanOutQueryResult, error) {
+	bulletins := make([]hexutil.Bytes, 0, len(result.Bulletins))
+	for _, b := range result.Bulletins {
+		bz, err := proto.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		bulletins = append(bulletins, bz)
+	}
+
+	return &FanOutQueryResult{
+		Bulletins:    bulletins,
+		Partial:      result.IsPartial(),
+		FailedShards: result.FailedShards,
+	}, nil
+}
+
+// remoteIP returns the IP of the client, or the whole remote address if it has no port
+func remoteIP(ctx context.Context) string {
+	addr := gethrpc.PeerInfoFromContext(ctx).RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
 // -----------------------------Only for test-----------------------------------------------
 
 func (g *ganyAPI) GetDelegatedAddr(mainAddr gethcmn.Address) (gethcmn.Address, error) {
@@ -141,6 +377,17 @@ func (g *ganyAPI) LoadWalletInStochasticPay(tokenAddr, ownerAddr gethcmn.Address
 	}, nil
 }
 
+// GetWalletEvents returns the deposits, withdrawals and payments of a wallet in StochasticPayVRF,
+// between the smartBCH heights `start` and `end`. Only the transactions sent to StochasticPayVRF directly are indexed.
+func (g *ganyAPI) GetWalletEvents(ownerAddr, tokenAddr gethcmn.Address, start, end int64) ([]*follower.WalletEvent, error) {
+	g.logger.Debug("gany_getWalletEvents")
+
+	if start < 0 || start > end {
+		return nil, fmt.Errorf("invalid height range [%d, %d]", start, end)
+	}
+	return g.backend.GetWalletEvents(ownerAddr, tokenAddr, start, end)
+}
+
 func (g *ganyAPI) GetValidatorPubKeyList() ([]hexutil.Bytes, error) {
 	g.logger.Debug("gany_getValidatorPubKeyList")
 	pubKeyList := g.backend.GetValidatorPubKeyList()
@@ -152,3 +399,114 @@ func (g *ganyAPI) GetValidatorPubKeyList() ([]hexutil.Bytes, error) {
 
 	return results, nil
 }
+
+// GetValidators returns the validators registered in the ganygov contract, with their gateways and stakes
+func (g *ganyAPI) GetValidators() []*Validator {
+	g.logger.Debug("gany_getValidators")
+	return toValidators(g.backend.GetValidators())
+}
+
+// GetValidatorSetAt returns the validator set in effect at the smartBCH height
+func (g *ganyAPI) GetValidatorSetAt(height hexutil.Uint64) (*ValidatorSet, error) {
+	g.logger.Debug("gany_getValidatorSetAt")
+
+	if uint64(height) > math.MaxInt64 {
+		return nil, fmt.Errorf("height %d overflows int64", height)
+	}
+	set, err := g.backend.GetValidatorSetAt(int64(height))
+	if err != nil {
+		return nil, err
+	}
+	return toValidatorSet(set), nil
+}
+
+// GetValidatorSetByRoot returns the validator set which the payments with this ValidatorPubkeyHashRoot are signed for
+func (g *ganyAPI) GetValidatorSetByRoot(root hexutil.Bytes) (*ValidatorSet, error) {
+	g.logger.Debug("gany_getValidatorSetByRoot")
+
+	set, err := g.backend.GetValidatorSetByRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	return toValidatorSet(set), nil
+}
+
+// GetValidatorSetByElectedTime returns the validator set in effect at the unix time, such as the time
+// which a payment is accepted for after an election
+func (g *ganyAPI) GetValidatorSetByElectedTime(electedTime hexutil.Uint64) (*ValidatorSet, error) {
+	g.logger.Debug("gany_getValidatorSetByElectedTime")
+
+	if uint64(electedTime) > math.MaxInt64 {
+		return nil, fmt.Errorf("elected time %d overflows int64", electedTime)
+	}
+	set, err := g.backend.GetValidatorSetByElectedTime(int64(electedTime))
+	if err != nil {
+		return nil, err
+	}
+	return toValidatorSet(set), nil
+}
+
+func toValidatorSet(set *follower.ValidatorSet) *ValidatorSet {
+	return &ValidatorSet{
+		ElectedTime: hexutil.Uint64(set.ElectedTime),
+		Height:      hexutil.Uint64(set.Height),
+		Root:        set.Root,
+		Validators:  toValidators(set.Validators),
+	}
+}
+
+func toValidators(validators []*follower.ValidatorInfo) []*Validator {
+	results := make([]*Validator, 0, len(validators))
+	for _, vi := range validators {
+		results = append(results, &Validator{
+			Address:        vi.Addr,
+			Pubkey:         vi.Pubkey,
+			RpcUrl:         vi.RpcUrlString(),
+			Intro:          vi.IntroString(),
+			TotalStakedAmt: (*hexutil.Big)(vi.TotalStakedAmt.ToBig()),
+			SelfStakedAmt:  (*hexutil.Big)(vi.SelfStakedAmt.ToBig()),
+			ElectedTime:    (*hexutil.Big)(vi.ElectedTime.ToBig()),
+			OldElectedTime: (*hexutil.Big)(vi.OldElectedTime.ToBig()),
+			Elected:        vi.IsElected(),
+		})
+	}
+	return results
+}
+
+// GetBulletinCacheStats returns the hit/miss counters of the bulletin cache
+func (g *ganyAPI) GetBulletinCacheStats() backend.BulletinCacheStats {
+	g.logger.Debug("gany_getBulletinCacheStats")
+	return g.backend.GetBulletinCacheStats()
+}
+
+// SyncStatus reports whether the smartBCH follower is caught up, which the balance checks depend on
+func (g *ganyAPI) SyncStatus() follower.SyncStatus {
+	g.logger.Debug("gany_syncStatus")
+	return g.backend.GetSyncStatus()
+}
+
+// GetUpstreamStats returns the health, the heights and the error counters of the upstream smartBCH endpoints
+func (g *ganyAPI) GetUpstreamStats() []web3client.EndpointStats {
+	g.logger.Debug("gany_getUpstreamStats")
+	return g.backend.GetUpstreamStats()
+}
+
+// Call runs a read-only contract call on the followed smartBCH state, at the latest followed block by default.
+// It shares the IP rate limit with PutBulletin, and the calls are bounded by the gas cap and the slots of the backend.
+func (g *ganyAPI) Call(ctx context.Context, args CallArgs, blockNr *gethrpc.BlockNumber) (hexutil.Bytes, error) {
+	g.logger.Debug("gany_call")
+
+	if err := g.backend.CheckIPRateLimit(remoteIP(ctx)); err != nil {
+		return nil, err
+	}
+
+	height := int64(follower.LatestHeight)
+	if blockNr != nil && *blockNr >= 0 {
+		height = blockNr.Int64()
+	}
+	result, err := g.backend.EthCall(args.toCallMsg(), height)
+	if err != nil {
+		return nil, err
+	}
+	return result.ReturnData, nil
+}
@@ -2,10 +2,12 @@ package cryptoutils
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	gethcrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/smartbch/merkletree"
+	"golang.org/x/crypto/sha3"
 )
 
 type MerkleLeaf struct {
@@ -26,6 +28,25 @@ func (l MerkleLeaf) Equals(other merkletree.Content) (bool, error) {
 	return bytes.Equal(l.Bz, other.(MerkleLeaf).Bz), nil
 }
 
+// NewValidatorPubKeysMerkleTree builds the Merkle tree of the validator set from the compressed public keys,
+// its root is the ValidatorPubkeyHashRoot which the payments are signed for
+func NewValidatorPubKeysMerkleTree(validatorPubKeys [][]byte) (*merkletree.MerkleTree, error) {
+	if len(validatorPubKeys) == 0 {
+		return nil, errors.New("no validators elected")
+	}
+
+	leaves := make([]merkletree.Content, 0, len(validatorPubKeys))
+	for _, cpk := range validatorPubKeys {
+		xy, err := CompressedPubKeyToXY(cpk)
+		if err != nil {
+			return nil, err
+		}
+
+		leaves = append(leaves, MerkleLeaf{Bz: xy})
+	}
+	return merkletree.NewTreeWithHashStrategy(leaves, sha3.NewLegacyKeccak256)
+}
+
 func VerifyContentExternal(from, rootHash []byte, proof [][]byte) (bool, error) {
 	var calHash [32]byte
 	fromHash := gethcrypto.Keccak256Hash(from)
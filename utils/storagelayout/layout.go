@@ -0,0 +1,101 @@
+// Package storagelayout reads the state variables of a followed contract by the storage layout of solc,
+// which is output with `solc --storage-layout` or `outputSelection: {"*": {"*": ["storageLayout"]}}`.
+package storagelayout
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// The encodings of the types
+const (
+	EncodingInplace      = "inplace"
+	EncodingMapping      = "mapping"
+	EncodingDynamicArray = "dynamic_array"
+	EncodingBytes        = "bytes" // bytes and string
+)
+
+// Layout is the storageLayout output of solc
+type Layout struct {
+	Storage []*Variable      `json:"storage"`
+	Types   map[string]*Type `json:"types"`
+}
+
+// Variable is a state variable or a struct member
+type Variable struct {
+	Label  string `json:"label"`
+	Offset uint   `json:"offset"` // in bytes, from the lower-order end of the slot
+	Slot   string `json:"slot"`   // decimal
+	Type   string `json:"type"`
+}
+
+type Type struct {
+	Encoding      string      `json:"encoding"`
+	Label         string      `json:"label"`
+	NumberOfBytes string      `json:"numberOfBytes"`   // decimal
+	Key           string      `json:"key,omitempty"`   // of a mapping
+	Value         string      `json:"value,omitempty"` // of a mapping
+	Base          string      `json:"base,omitempty"`  // of an array
+	Members       []*Variable `json:"members,omitempty"`
+
+	size uint64
+}
+
+// ParseLayout parses and checks the storageLayout JSON
+func ParseLayout(bz []byte) (*Layout, error) {
+	var layout Layout
+	if err := json.Unmarshal(bz, &layout); err != nil {
+		return nil, err
+	}
+	for id, typ := range layout.Types {
+		size, err := strconv.ParseUint(typ.NumberOfBytes, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid numberOfBytes of %s: %w", id, err)
+		}
+		typ.size = size
+	}
+	if err := layout.checkTypes(layout.Storage); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// MustParseLayout is ParseLayout for the layouts compiled into the binary
+func MustParseLayout(s string) *Layout {
+	layout, err := ParseLayout([]byte(s))
+	if err != nil {
+		panic(err)
+	}
+	return layout
+}
+
+func (layout *Layout) checkTypes(vars []*Variable) error {
+	for _, v := range vars {
+		if _, ok := layout.Types[v.Type]; !ok {
+			return fmt.Errorf("unknown type %s of %s", v.Type, v.Label)
+		}
+	}
+	for id, typ := range layout.Types {
+		for _, ref := range []string{typ.Key, typ.Value, typ.Base} {
+			if _, ok := layout.Types[ref]; ref != "" && !ok {
+				return fmt.Errorf("unknown type %s referred by %s", ref, id)
+			}
+		}
+		for _, m := range typ.Members {
+			if _, ok := layout.Types[m.Type]; !ok {
+				return fmt.Errorf("unknown type %s of %s.%s", m.Type, id, m.Label)
+			}
+		}
+	}
+	return nil
+}
+
+func findVariable(vars []*Variable, label string) (*Variable, bool) {
+	for _, v := range vars {
+		if v.Label == label {
+			return v, true
+		}
+	}
+	return nil, false
+}
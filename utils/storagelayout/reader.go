@@ -0,0 +1,228 @@
+package storagelayout
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+// Storage is the state of the contracts, which is implemented by mevmtypes.Context
+type Storage interface {
+	GetStorageAt(seq uint64, key string) []byte
+}
+
+// Reader reads the state variables of a contract
+type Reader struct {
+	layout  *Layout
+	storage Storage
+	seq     uint64 // the sequence of the contract in moeingevm
+}
+
+func NewReader(layout *Layout, storage Storage, seq uint64) *Reader {
+	return &Reader{layout: layout, storage: storage, seq: seq}
+}
+
+// Value is the location of a variable, a member, an element or a mapping value
+type Value struct {
+	reader *Reader
+	typ    *Type
+	label  string
+	slot   *uint256.Int
+	offset uint
+}
+
+// Var returns the state variable
+func (r *Reader) Var(label string) (*Value, error) {
+	v, ok := findVariable(r.layout.Storage, label)
+	if !ok {
+		return nil, fmt.Errorf("no state variable %s", label)
+	}
+	return r.valueOf(v, uint256.NewInt(0))
+}
+
+func (r *Reader) valueOf(v *Variable, base *uint256.Int) (*Value, error) {
+	slotBig, ok := new(big.Int).SetString(v.Slot, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid slot %s of %s", v.Slot, v.Label)
+	}
+	slot, overflow := uint256.FromBig(slotBig)
+	if overflow {
+		return nil, fmt.Errorf("invalid slot %s of %s", v.Slot, v.Label)
+	}
+	return &Value{
+		reader: r,
+		typ:    r.layout.Types[v.Type],
+		label:  v.Label,
+		slot:   slot.Add(slot, base),
+		offset: v.Offset,
+	}, nil
+}
+
+func (r *Reader) readSlot(slot *uint256.Int) []byte {
+	bz := r.storage.GetStorageAt(r.seq, string(slot.PaddedBytes(32)))
+	return gethcmn.LeftPadBytes(bz, 32)
+}
+
+// ----------------------------------------------------------------
+
+func (v *Value) Type() *Type {
+	return v.typ
+}
+
+// Member returns the member of a struct
+func (v *Value) Member(label string) (*Value, error) {
+	m, ok := findVariable(v.typ.Members, label)
+	if !ok {
+		return nil, fmt.Errorf("%s (%s) has no member %s", v.label, v.typ.Label, label)
+	}
+	return v.reader.valueOf(m, v.slot)
+}
+
+// Key returns the value of a mapping. The key is the bytes of the value type, e.g. 20 bytes of an address,
+// or the content of a string or bytes.
+func (v *Value) Key(key []byte) (*Value, error) {
+	if v.typ.Encoding != EncodingMapping {
+		return nil, fmt.Errorf("%s (%s) is not a mapping", v.label, v.typ.Label)
+	}
+
+	keyType := v.reader.layout.Types[v.typ.Key]
+	var preimage []byte
+	switch {
+	case keyType.Encoding == EncodingBytes:
+		preimage = append(preimage, key...)
+	case strings.HasPrefix(v.typ.Key, "t_bytes"): // bytes1 ~ bytes32 are left-aligned
+		preimage = gethcmn.RightPadBytes(key, 32)
+	default:
+		preimage = gethcmn.LeftPadBytes(key, 32)
+	}
+	preimage = append(preimage, v.slot.PaddedBytes(32)...)
+
+	return &Value{
+		reader: v.reader,
+		typ:    v.reader.layout.Types[v.typ.Value],
+		label:  fmt.Sprintf("%s[0x%x]", v.label, key),
+		slot:   uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(preimage)),
+	}, nil
+}
+
+// Len returns the length of a dynamic array
+func (v *Value) Len() (uint64, error) {
+	if v.typ.Encoding != EncodingDynamicArray {
+		return 0, fmt.Errorf("%s (%s) is not a dynamic array", v.label, v.typ.Label)
+	}
+	n := uint256.NewInt(0).SetBytes(v.reader.readSlot(v.slot))
+	if !n.IsUint64() {
+		return 0, fmt.Errorf("invalid length of %s", v.label)
+	}
+	return n.Uint64(), nil
+}
+
+// Index returns the element of an array. The elements no larger than 16 bytes are packed into the slots.
+func (v *Value) Index(i uint64) (*Value, error) {
+	if v.typ.Base == "" {
+		return nil, fmt.Errorf("%s (%s) is not an array", v.label, v.typ.Label)
+	}
+
+	start := v.slot
+	if v.typ.Encoding == EncodingDynamicArray {
+		n, err := v.Len()
+		if err != nil {
+			return nil, err
+		}
+		if i >= n {
+			return nil, fmt.Errorf("index %d out of range of %s (length %d)", i, v.label, n)
+		}
+		start = uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(v.slot.PaddedBytes(32)))
+	}
+
+	base := v.reader.layout.Types[v.typ.Base]
+	elem := &Value{
+		reader: v.reader,
+		typ:    base,
+		label:  fmt.Sprintf("%s[%d]", v.label, i),
+	}
+	if base.size == 0 {
+		return nil, fmt.Errorf("invalid element size of %s", v.label)
+	} else if base.size <= 16 {
+		perSlot := 32 / base.size
+		elem.slot = uint256.NewInt(0).AddUint64(start, i/perSlot)
+		elem.offset = uint((i % perSlot) * base.size)
+	} else {
+		words := (base.size + 31) / 32
+		elem.slot = uint256.NewInt(0).AddUint64(start, i*words)
+	}
+	return elem, nil
+}
+
+// Bytes returns the content of a value type in its numberOfBytes, or the content of a string or bytes
+func (v *Value) Bytes() ([]byte, error) {
+	switch v.typ.Encoding {
+	case EncodingInplace:
+		if len(v.typ.Members) != 0 || v.typ.Base != "" || v.typ.size > 32 {
+			return nil, fmt.Errorf("%s (%s) is not a value type", v.label, v.typ.Label)
+		}
+		word := v.reader.readSlot(v.slot)
+		end := 32 - uint64(v.offset)
+		if uint64(v.offset)+v.typ.size > 32 {
+			return nil, fmt.Errorf("invalid offset of %s", v.label)
+		}
+		return word[end-v.typ.size : end], nil
+	case EncodingBytes:
+		return v.readBytes()
+	}
+	return nil, fmt.Errorf("%s (%s) is not a value type", v.label, v.typ.Label)
+}
+
+// a short content is stored with length*2 in the lowest byte, a long content is stored at keccak256(slot) with length*2+1
+func (v *Value) readBytes() ([]byte, error) {
+	word := v.reader.readSlot(v.slot)
+	if word[31]&1 == 0 {
+		n := word[31] / 2
+		if n > 31 {
+			return nil, errors.New("invalid length of short bytes")
+		}
+		return word[:n], nil
+	}
+
+	n := uint256.NewInt(0).SetBytes(word)
+	n.Rsh(n, 1)
+	if !n.IsUint64() || n.Uint64() > 1<<24 {
+		return nil, fmt.Errorf("invalid length of %s", v.label)
+	}
+	length := n.Uint64()
+	start := uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(v.slot.PaddedBytes(32)))
+	result := make([]byte, 0, length+31)
+	for i := uint64(0); i*32 < length; i++ {
+		result = append(result, v.reader.readSlot(uint256.NewInt(0).AddUint64(start, i))...)
+	}
+	return result[:length], nil
+}
+
+func (v *Value) Uint256() (*uint256.Int, error) {
+	bz, err := v.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return uint256.NewInt(0).SetBytes(bz), nil
+}
+
+func (v *Value) Address() (gethcmn.Address, error) {
+	bz, err := v.Bytes()
+	if err != nil {
+		return gethcmn.Address{}, err
+	}
+	return gethcmn.BytesToAddress(bz), nil
+}
+
+func (v *Value) Bool() (bool, error) {
+	bz, err := v.Bytes()
+	if err != nil {
+		return false, err
+	}
+	return len(bz) == 1 && bz[0] != 0, nil
+}
@@ -0,0 +1,195 @@
+package storagelayout
+
+import (
+	"strings"
+	"testing"
+
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+//	contract Test {
+//	    struct Info { address addr; uint8 kind; bool active; uint amount; }
+//	    uint64 a; bool b; address c;           // packed into slot 0
+//	    mapping(address => uint) balances;     // slot 1
+//	    Info[] infos;                          // slot 2
+//	    uint64[3] fixed;                       // slot 3
+//	    string name;                           // slot 4
+//	    mapping(string => Info) infoByName;    // slot 5
+//	}
+const testLayout = `{
+  "storage": [
+    {"label": "a", "offset": 0, "slot": "0", "type": "t_uint64"},
+    {"label": "b", "offset": 8, "slot": "0", "type": "t_bool"},
+    {"label": "c", "offset": 9, "slot": "0", "type": "t_address"},
+    {"label": "balances", "offset": 0, "slot": "1", "type": "t_mapping(t_address,t_uint256)"},
+    {"label": "infos", "offset": 0, "slot": "2", "type": "t_array(t_struct(Info)_storage)dyn_storage"},
+    {"label": "fixed", "offset": 0, "slot": "3", "type": "t_array(t_uint64)3_storage"},
+    {"label": "name", "offset": 0, "slot": "4", "type": "t_string_storage"},
+    {"label": "infoByName", "offset": 0, "slot": "5", "type": "t_mapping(t_string_memory_ptr,t_struct(Info)_storage)"}
+  ],
+  "types": {
+    "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+    "t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+    "t_uint8": {"encoding": "inplace", "label": "uint8", "numberOfBytes": "1"},
+    "t_uint64": {"encoding": "inplace", "label": "uint64", "numberOfBytes": "8"},
+    "t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+    "t_string_storage": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+    "t_string_memory_ptr": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+    "t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "value": "t_uint256", "label": "mapping(address => uint256)", "numberOfBytes": "32"},
+    "t_mapping(t_string_memory_ptr,t_struct(Info)_storage)": {"encoding": "mapping", "key": "t_string_memory_ptr", "value": "t_struct(Info)_storage", "label": "mapping(string => struct Test.Info)", "numberOfBytes": "32"},
+    "t_array(t_struct(Info)_storage)dyn_storage": {"encoding": "dynamic_array", "base": "t_struct(Info)_storage", "label": "struct Test.Info[]", "numberOfBytes": "32"},
+    "t_array(t_uint64)3_storage": {"encoding": "inplace", "base": "t_uint64", "label": "uint64[3]", "numberOfBytes": "32"},
+    "t_struct(Info)_storage": {
+      "encoding": "inplace", "label": "struct Test.Info", "numberOfBytes": "64",
+      "members": [
+        {"label": "addr", "offset": 0, "slot": "0", "type": "t_address"},
+        {"label": "kind", "offset": 20, "slot": "0", "type": "t_uint8"},
+        {"label": "active", "offset": 21, "slot": "0", "type": "t_bool"},
+        {"label": "amount", "offset": 0, "slot": "1", "type": "t_uint256"}
+      ]
+    }
+  }
+}`
+
+type mapStorage map[string][]byte
+
+func (s mapStorage) GetStorageAt(seq uint64, key string) []byte {
+	return s[key]
+}
+
+func (s mapStorage) set(slot *uint256.Int, value []byte) {
+	s[string(slot.PaddedBytes(32))] = gethcmn.LeftPadBytes(value, 32)
+}
+
+func keccakSlot(parts ...[]byte) *uint256.Int {
+	return uint256.NewInt(0).SetBytes(gethcrypto.Keccak256(parts...))
+}
+
+func TestReader(t *testing.T) {
+	layout, err := ParseLayout([]byte(testLayout))
+	require.NoError(t, err)
+
+	addr := gethcmn.HexToAddress("0x06C14ED469FB93545cbF071b593D8f90194Ede62")
+	storage := mapStorage{}
+
+	// a = 7, b = true, c = addr
+	slot0 := make([]byte, 32)
+	slot0[31] = 7
+	slot0[23] = 1
+	copy(slot0[3:23], addr.Bytes())
+	storage.set(uint256.NewInt(0), slot0)
+
+	// balances[addr] = 100
+	storage.set(keccakSlot(gethcmn.LeftPadBytes(addr.Bytes(), 32), uint256.NewInt(1).PaddedBytes(32)), []byte{100})
+
+	// infos = [_, {addr, 3, true, 200}]
+	storage.set(uint256.NewInt(2), []byte{2})
+	infos := keccakSlot(uint256.NewInt(2).PaddedBytes(32))
+	info := append([]byte{1, 3}, addr.Bytes()...)
+	storage.set(uint256.NewInt(0).AddUint64(infos, 2), info)
+	storage.set(uint256.NewInt(0).AddUint64(infos, 3), []byte{200})
+
+	// fixed = [1, 2, 3]
+	storage.set(uint256.NewInt(3), []byte{0, 0, 0, 0, 0, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 1})
+
+	// name is a long string
+	name := strings.Repeat("gany", 10)
+	storage.set(uint256.NewInt(4), []byte{byte(len(name)*2 + 1)})
+	nameSlot := keccakSlot(uint256.NewInt(4).PaddedBytes(32))
+	storage[string(nameSlot.PaddedBytes(32))] = []byte(name[:32])
+	storage[string(uint256.NewInt(0).AddUint64(nameSlot, 1).PaddedBytes(32))] = gethcmn.RightPadBytes([]byte(name[32:]), 32)
+
+	// infoByName["gany"].amount = 300
+	infoByName := keccakSlot([]byte("gany"), uint256.NewInt(5).PaddedBytes(32))
+	storage.set(uint256.NewInt(0).AddUint64(infoByName, 1), []byte{0x01, 0x2c})
+
+	reader := NewReader(layout, storage, 0)
+
+	a, err := reader.Var("a")
+	require.NoError(t, err)
+	aVal, err := a.Uint256()
+	require.NoError(t, err)
+	require.EqualValues(t, 7, aVal.Uint64())
+	b, _ := reader.Var("b")
+	bVal, err := b.Bool()
+	require.NoError(t, err)
+	require.True(t, bVal)
+	c, _ := reader.Var("c")
+	cVal, err := c.Address()
+	require.NoError(t, err)
+	require.Equal(t, addr, cVal)
+
+	balances, _ := reader.Var("balances")
+	balance, err := balances.Key(addr.Bytes())
+	require.NoError(t, err)
+	balanceVal, _ := balance.Uint256()
+	require.EqualValues(t, 100, balanceVal.Uint64())
+	_, err = a.Key(addr.Bytes())
+	require.Error(t, err)
+
+	infosVar, _ := reader.Var("infos")
+	n, err := infosVar.Len()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+	_, err = infosVar.Index(2)
+	require.Error(t, err)
+	info1, err := infosVar.Index(1)
+	require.NoError(t, err)
+	member, err := info1.Member("addr")
+	require.NoError(t, err)
+	memberAddr, _ := member.Address()
+	require.Equal(t, addr, memberAddr)
+	member, _ = info1.Member("kind")
+	kind, _ := member.Uint256()
+	require.EqualValues(t, 3, kind.Uint64())
+	member, _ = info1.Member("active")
+	active, _ := member.Bool()
+	require.True(t, active)
+	member, _ = info1.Member("amount")
+	amount, _ := member.Uint256()
+	require.EqualValues(t, 200, amount.Uint64())
+	_, err = info1.Member("nothing")
+	require.Error(t, err)
+	_, err = info1.Bytes()
+	require.Error(t, err)
+
+	fixed, _ := reader.Var("fixed")
+	for i := uint64(0); i < 3; i++ {
+		elem, err := fixed.Index(i)
+		require.NoError(t, err)
+		v, _ := elem.Uint256()
+		require.EqualValues(t, i+1, v.Uint64())
+	}
+
+	nameVar, _ := reader.Var("name")
+	nameVal, err := nameVar.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, name, string(nameVal))
+
+	infoByNameVar, _ := reader.Var("infoByName")
+	gany, err := infoByNameVar.Key([]byte("gany"))
+	require.NoError(t, err)
+	member, _ = gany.Member("amount")
+	amount, _ = member.Uint256()
+	require.EqualValues(t, 300, amount.Uint64())
+
+	// empty
+	storage = mapStorage{}
+	reader = NewReader(layout, storage, 0)
+	nameVar, _ = reader.Var("name")
+	nameVal, err = nameVar.Bytes()
+	require.NoError(t, err)
+	require.Len(t, nameVal, 0)
+	_, err = reader.Var("nothing")
+	require.Error(t, err)
+}
+
+func TestParseLayout(t *testing.T) {
+	_, err := ParseLayout([]byte(`{"storage": [{"label": "a", "slot": "0", "type": "t_uint256"}], "types": {}}`))
+	require.Error(t, err)
+	_, err = ParseLayout([]byte(`{"storage": [], "types": {"t_uint256": {"encoding": "inplace", "numberOfBytes": "x"}}}`))
+	require.Error(t, err)
+}
@@ -27,7 +27,7 @@ func NewMockGanyApp(app *app.GanyApplication) *MockGanyApp {
 }
 
 func CreateMockGanyApp(db *badger.DB) *MockGanyApp {
-	gApp := app.NewGanyApplication(db, "10000", tmlog.MustNewDefaultLogger(tmlog.LogFormatPlain, tmlog.LogLevelInfo, false))
+	gApp := app.NewGanyApplication(db, "10000", app.DefaultConfig(), nil, tmlog.MustNewDefaultLogger(tmlog.LogFormatPlain, tmlog.LogLevelInfo, false))
 	return NewMockGanyApp(gApp)
 }
 
@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/holiman/uint256"
 	tmbytes "github.com/tendermint/tendermint/libs/bytes"
+	tmlog "github.com/tendermint/tendermint/libs/log"
 	"github.com/vechain/go-ecvrf"
 
 	"github.com/smartbch/ganychain/app"
@@ -40,7 +41,8 @@ type MockBackend struct {
 func NewMockBackend(apps []app.GanyApp, follower follower.FollowerService, sbchClient web3client.Web3Client,
 	token, contractAddr, validatorAddr gethcmn.Address, validatorPrivateKey *ecdsa.PrivateKey) *MockBackend {
 
-	be := backend.NewBackend(apps, follower, sbchClient)
+	be := backend.NewBackend(apps, follower, sbchClient, backend.DefaultConfig(),
+		tmlog.MustNewDefaultLogger(tmlog.LogFormatPlain, tmlog.LogLevelInfo, false))
 	return &MockBackend{
 		Backend:             be.(*backend.Backend),
 		numOfShards:         uint32(len(apps)),
@@ -211,7 +213,7 @@ func (m *MockBackend) checkAuth(sp *pb.StochasticPayment, b *pb.Bulletin, ap *pb
 			return errors.New("incorrect auth challenge hash")
 		}
 
-		if !ap.CheckAuthProof(sp, b) {
+		if !ap.CheckAuthProof(sp, b, backend.NewChainRegistry(m.follower)) {
 			return errors.New("incorrect auth proof")
 		}
 	}
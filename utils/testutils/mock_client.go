@@ -8,6 +8,8 @@ import (
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	modbtypes "github.com/smartbch/moeingdb/types"
+
+	"github.com/smartbch/ganychain/web3client"
 )
 
 type MockClient struct{}
@@ -85,3 +87,7 @@ func (client *MockClient) GeLatestBlockHeight() (int64, error) {
 	//TODO implement me
 	panic("not implemented")
 }
+
+func (client *MockClient) GetEndpointStats() []web3client.EndpointStats {
+	return nil
+}
@@ -1,10 +1,15 @@
 package testutils
 
 import (
+	"bytes"
 	"errors"
+	"time"
 
+	geth "github.com/ethereum/go-ethereum"
 	gethcmn "github.com/ethereum/go-ethereum/common"
 	"github.com/holiman/uint256"
+
+	"github.com/smartbch/ganychain/follower"
 )
 
 // to simplify testing, default is one token
@@ -17,6 +22,17 @@ type MockFollower struct {
 	addrMap          map[gethcmn.Address]gethcmn.Address
 	wallets          map[gethcmn.Address]*wallet
 	validatorPubKeys [][]byte // compressed public keys
+	validators       []*follower.ValidatorInfo
+
+	prevValidatorPubKeys [][]byte
+	electedTime          int64
+	validatorSets        []*follower.ValidatorSet // in the order of height
+
+	callResults map[string][]byte // to + data => output
+	blockTime   int64
+	syncStatus  follower.SyncStatus
+
+	walletEvents []*follower.WalletEvent
 }
 
 func NewMockFollower(addrMap map[gethcmn.Address]gethcmn.Address, validatorPubKeys [][]byte) *MockFollower {
@@ -24,6 +40,8 @@ func NewMockFollower(addrMap map[gethcmn.Address]gethcmn.Address, validatorPubKe
 		addrMap:          addrMap,
 		wallets:          make(map[gethcmn.Address]*wallet),
 		validatorPubKeys: validatorPubKeys,
+		callResults:      make(map[string][]byte),
+		blockTime:        time.Now().Unix(),
 	}
 }
 
@@ -53,3 +71,119 @@ func (m *MockFollower) LoadWalletInStochasticPay(tokenAddress, ownerAddress geth
 func (m *MockFollower) GetValidatorPubKeyList() [][]byte {
 	return m.validatorPubKeys
 }
+
+func (m *MockFollower) SetValidators(validators []*follower.ValidatorInfo) {
+	m.validators = validators
+}
+
+func (m *MockFollower) GetValidators() []*follower.ValidatorInfo {
+	return m.validators
+}
+
+func (m *MockFollower) SetPreviousValidatorPubKeys(validatorPubKeys [][]byte, electedTime int64) {
+	m.prevValidatorPubKeys = validatorPubKeys
+	m.electedTime = electedTime
+}
+
+func (m *MockFollower) GetPreviousValidatorPubKeyList() ([][]byte, int64) {
+	return m.prevValidatorPubKeys, m.electedTime
+}
+
+func (m *MockFollower) AddValidatorSet(set *follower.ValidatorSet) {
+	m.validatorSets = append(m.validatorSets, set)
+}
+
+func (m *MockFollower) GetValidatorSetAt(height int64) (*follower.ValidatorSet, error) {
+	for i := len(m.validatorSets) - 1; i >= 0; i-- {
+		if m.validatorSets[i].Height <= height {
+			return m.validatorSets[i], nil
+		}
+	}
+	return nil, follower.ErrValidatorSetNotFound
+}
+
+func (m *MockFollower) GetValidatorSetByRoot(root []byte) (*follower.ValidatorSet, error) {
+	for i := len(m.validatorSets) - 1; i >= 0; i-- {
+		if bytes.Equal(m.validatorSets[i].Root, root) {
+			return m.validatorSets[i], nil
+		}
+	}
+	return nil, follower.ErrValidatorSetNotFound
+}
+
+func (m *MockFollower) GetValidatorSetByElectedTime(electedTime int64) (*follower.ValidatorSet, error) {
+	for i := len(m.validatorSets) - 1; i >= 0; i-- {
+		if m.validatorSets[i].ElectedTime <= electedTime {
+			return m.validatorSets[i], nil
+		}
+	}
+	return nil, follower.ErrValidatorSetNotFound
+}
+
+func (m *MockFollower) GetChainId() *uint256.Int {
+	return uint256.NewInt(follower.SBCHChainId)
+}
+
+// SetCallResult sets the output of the calls to `to` with `data`, the calls return an error if no output is set
+func (m *MockFollower) SetCallResult(to gethcmn.Address, data, outData []byte) {
+	m.callResults[string(to.Bytes())+string(data)] = outData
+}
+
+func (m *MockFollower) SetBlockTime(blockTime int64) {
+	m.blockTime = blockTime
+}
+
+func (m *MockFollower) GetLatestBlockTime() int64 {
+	return m.blockTime
+}
+
+func (m *MockFollower) CallContract(from, to gethcmn.Address, data []byte) ([]byte, int64, error) {
+	outData, found := m.callResults[string(to.Bytes())+string(data)]
+	if !found {
+		return nil, 0, errors.New("call failed")
+	}
+	return outData, m.blockTime, nil
+}
+
+func (m *MockFollower) EthCall(msg geth.CallMsg, height int64) (*follower.CallResult, error) {
+	if msg.To == nil {
+		return nil, errors.New("contract creation is not supported in a call")
+	}
+	outData, blockTime, err := m.CallContract(msg.From, *msg.To, msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	return &follower.CallResult{ReturnData: outData, Height: m.syncStatus.SyncedHeight, BlockTime: blockTime}, nil
+}
+
+func (m *MockFollower) SetSyncStatus(syncedHeight, upstreamHeight int64) {
+	m.syncStatus = follower.SyncStatus{
+		SyncedHeight:   syncedHeight,
+		UpstreamHeight: upstreamHeight,
+		Lag:            upstreamHeight - syncedHeight,
+		State:          follower.SyncStateLive,
+	}
+}
+
+func (m *MockFollower) GetSyncStatus() follower.SyncStatus {
+	status := m.syncStatus
+	status.LatestBlockTime = m.blockTime
+	if status.State == "" {
+		status.State = follower.SyncStateLive
+	}
+	return status
+}
+
+func (m *MockFollower) AddWalletEvent(e *follower.WalletEvent) {
+	m.walletEvents = append(m.walletEvents, e)
+}
+
+func (m *MockFollower) GetWalletEvents(owner, token gethcmn.Address, startHeight, endHeight int64, limit int) ([]*follower.WalletEvent, error) {
+	var events []*follower.WalletEvent
+	for _, e := range m.walletEvents {
+		if e.Owner == owner && e.Token == token && e.Height >= startHeight && e.Height <= endHeight && len(events) < limit {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,342 @@
+package web3client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	modbtypes "github.com/smartbch/moeingdb/types"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+const (
+	HealthCheckInterval = 6 * time.Second // about a smartBCH block
+
+	// the endpoints behind the highest one by more than this are only used if the others fail
+	MaxEndpointLag = 2
+)
+
+// EndpointStats are the health and the counters of an upstream endpoint since the node started
+type EndpointStats struct {
+	Url         string `json:"url"`
+	Healthy     bool   `json:"healthy"`
+	Height      int64  `json:"height"` // the latest block reported by the endpoint
+	Requests    uint64 `json:"requests"`
+	Errors      uint64 `json:"errors"`
+	LastError   string `json:"lastError,omitempty"`
+	LastErrorAt int64  `json:"lastErrorAt,omitempty"` // unix time
+}
+
+type endpoint struct {
+	url    string
+	index  int // the position in the configured list, the earlier is preferred
+	client EndpointClient
+
+	mtx   sync.Mutex
+	stats EndpointStats
+}
+
+// onResult records the result of a request, it returns whether the endpoint was healthy before
+func (e *endpoint) onResult(err error, now time.Time) bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	healthy := e.stats.Healthy
+	e.stats.Requests++
+	if err != nil {
+		e.stats.Errors++
+		e.stats.Healthy = false
+		e.stats.LastError = err.Error()
+		e.stats.LastErrorAt = now.Unix()
+	}
+	return healthy
+}
+
+// onHeight records the height reported by a successful health check, it returns whether the endpoint was healthy before
+func (e *endpoint) onHeight(height int64) bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	healthy := e.stats.Healthy
+	e.stats.Healthy, e.stats.Height = true, height
+	return healthy
+}
+
+func (e *endpoint) getStats() EndpointStats {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.stats
+}
+
+// MultiClient routes each call to the best smartBCH endpoint, and fails over to the others if the endpoint
+// does not answer. The best endpoint is the first healthy one in the configured list which is not lagging.
+// An endpoint is marked unhealthy when a call to it fails, and healthy again when a health check succeeds.
+type MultiClient struct {
+	endpoints []*endpoint
+	logger    tmlog.Logger
+	now       func() time.Time
+}
+
+var _ Web3Client = (*MultiClient)(nil)
+
+// NewMultiClient dials the endpoints, the URLs must be http(s) ones since sbch_getSyncBlock is sent over HTTP
+func NewMultiClient(urls []string, logger tmlog.Logger) (*MultiClient, error) {
+	return newMultiClient(urls, func(url string) (EndpointClient, error) {
+		return DialSbchClient(url)
+	}, logger)
+}
+
+func newMultiClient(urls []string, dial func(url string) (EndpointClient, error), logger tmlog.Logger) (*MultiClient, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no smartBCH endpoint is configured")
+	}
+	c := &MultiClient{logger: logger, now: time.Now}
+	for i, url := range urls {
+		client, err := dial(url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial %s: %w", url, err)
+		}
+		c.endpoints = append(c.endpoints, &endpoint{
+			url:    url,
+			index:  i,
+			client: client,
+			stats:  EndpointStats{Url: url, Healthy: true},
+		})
+	}
+	return c, nil
+}
+
+// Run checks the health and the heights of the endpoints periodically, it never returns until ctx is done
+func (c *MultiClient) Run(ctx context.Context) {
+	ticker := time.NewTicker(HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		c.checkHealth()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *MultiClient) checkHealth() {
+	var wg sync.WaitGroup
+	for _, e := range c.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			height, err := e.client.GeLatestBlockHeight()
+			if wasHealthy := e.onResult(err, c.now()); err != nil {
+				if wasHealthy {
+					c.logger.Error("smartBCH endpoint is unhealthy", "url", e.url, "error", err)
+				}
+			} else if !e.onHeight(height) {
+				c.logger.Info("smartBCH endpoint recovered", "url", e.url, "height", height)
+			}
+		}(e)
+	}
+	wg.Wait()
+}
+
+// candidates returns the endpoints in the order they are tried
+func (c *MultiClient) candidates() []*endpoint {
+	stats := make(map[*endpoint]EndpointStats, len(c.endpoints))
+	maxHeight := int64(0)
+	for _, e := range c.endpoints {
+		s := e.getStats()
+		stats[e] = s
+		if s.Healthy && s.Height > maxHeight {
+			maxHeight = s.Height
+		}
+	}
+	rank := func(e *endpoint) int {
+		s := stats[e]
+		switch {
+		case s.Healthy && s.Height >= maxHeight-MaxEndpointLag:
+			return 0
+		case s.Healthy:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	candidates := append([]*endpoint{}, c.endpoints...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := rank(candidates[i]), rank(candidates[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if ri == 1 {
+			return stats[candidates[i]].Height > stats[candidates[j]].Height
+		}
+		return candidates[i].index < candidates[j].index
+	})
+	return candidates
+}
+
+// do calls `f` with the candidates in order until an endpoint answers
+func (c *MultiClient) do(ctx context.Context, method string, f func(client EndpointClient) error) error {
+	var err error
+	for i, e := range c.candidates() {
+		if i > 0 {
+			c.logger.Info("failing over to the next smartBCH endpoint", "method", method, "url", e.url)
+		}
+		err = f(e.client)
+		if ctxErr := ctx.Err(); ctxErr != nil && err != nil {
+			// the call is given up by the caller, an endpoint which hangs until the deadline is failed
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				e.onResult(err, c.now())
+			}
+			return err
+		}
+		if !isEndpointError(err) {
+			e.onResult(nil, c.now())
+			return err
+		}
+		e.onResult(err, c.now())
+		c.logger.Error("smartBCH endpoint failed", "method", method, "url", e.url, "error", err)
+	}
+	return err
+}
+
+// isEndpointError tells whether another endpoint may answer the call. The errors returned by the node,
+// such as an execution revert, or a missing receipt of a pending tx, are the answer of the call.
+func isEndpointError(err error) bool {
+	if err == nil || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	var rpcErr gethrpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// GetEndpointStats returns the stats of the endpoints in the configured order
+func (c *MultiClient) GetEndpointStats() []EndpointStats {
+	stats := make([]EndpointStats, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		stats = append(stats, e.getStats())
+	}
+	return stats
+}
+
+func (c *MultiClient) TransactionReceipt(ctx context.Context, txHash gethcmn.Hash) (receipt *gethtypes.Receipt, err error) {
+	err = c.do(ctx, "TransactionReceipt", func(client EndpointClient) (err error) {
+		receipt, err = client.TransactionReceipt(ctx, txHash)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) CodeAt(ctx context.Context, account gethcmn.Address, blockNumber *big.Int) (code []byte, err error) {
+	err = c.do(ctx, "CodeAt", func(client EndpointClient) (err error) {
+		code, err = client.CodeAt(ctx, account, blockNumber)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (out []byte, err error) {
+	err = c.do(ctx, "CallContract", func(client EndpointClient) (err error) {
+		out, err = client.CallContract(ctx, call, blockNumber)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) HeaderByNumber(ctx context.Context, number *big.Int) (header *gethtypes.Header, err error) {
+	err = c.do(ctx, "HeaderByNumber", func(client EndpointClient) (err error) {
+		header, err = client.HeaderByNumber(ctx, number)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) PendingCodeAt(ctx context.Context, account gethcmn.Address) (code []byte, err error) {
+	err = c.do(ctx, "PendingCodeAt", func(client EndpointClient) (err error) {
+		code, err = client.PendingCodeAt(ctx, account)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) PendingNonceAt(ctx context.Context, account gethcmn.Address) (nonce uint64, err error) {
+	err = c.do(ctx, "PendingNonceAt", func(client EndpointClient) (err error) {
+		nonce, err = client.PendingNonceAt(ctx, account)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) SuggestGasPrice(ctx context.Context) (price *big.Int, err error) {
+	err = c.do(ctx, "SuggestGasPrice", func(client EndpointClient) (err error) {
+		price, err = client.SuggestGasPrice(ctx)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) SuggestGasTipCap(ctx context.Context) (tip *big.Int, err error) {
+	err = c.do(ctx, "SuggestGasTipCap", func(client EndpointClient) (err error) {
+		tip, err = client.SuggestGasTipCap(ctx)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (gas uint64, err error) {
+	err = c.do(ctx, "EstimateGas", func(client EndpointClient) (err error) {
+		gas, err = client.EstimateGas(ctx, call)
+		return
+	})
+	return
+}
+
+// SendTransaction may send the signed tx to more than one endpoint, which is harmless since it is the same tx
+func (c *MultiClient) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	return c.do(ctx, "SendTransaction", func(client EndpointClient) error {
+		return client.SendTransaction(ctx, tx)
+	})
+}
+
+func (c *MultiClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) (logs []gethtypes.Log, err error) {
+	err = c.do(ctx, "FilterLogs", func(client EndpointClient) (err error) {
+		logs, err = client.FilterLogs(ctx, query)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery,
+	ch chan<- gethtypes.Log) (sub ethereum.Subscription, err error) {
+
+	err = c.do(ctx, "SubscribeFilterLogs", func(client EndpointClient) (err error) {
+		sub, err = client.SubscribeFilterLogs(ctx, query, ch)
+		return
+	})
+	return
+}
+
+func (c *MultiClient) GeLatestBlockHeight() (height int64, err error) {
+	err = c.do(context.Background(), "GeLatestBlockHeight", func(client EndpointClient) (err error) {
+		height, err = client.GeLatestBlockHeight()
+		return
+	})
+	return
+}
+
+// GetSyncBlock fails over if the endpoint is unreachable. A block not produced yet is answered with ethereum.NotFound,
+// which is not failed over, the lagging endpoints are skipped by the health check instead.
+func (c *MultiClient) GetSyncBlock(height uint64) (blk *modbtypes.ExtendedBlock, err error) {
+	err = c.do(context.Background(), "GetSyncBlock", func(client EndpointClient) (err error) {
+		blk, err = client.GetSyncBlock(height)
+		return
+	})
+	return
+}
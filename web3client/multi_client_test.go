@@ -0,0 +1,177 @@
+package web3client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	gethcmn "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	modbtypes "github.com/smartbch/moeingdb/types"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+type rpcError struct{}
+
+func (rpcError) Error() string  { return "execution reverted" }
+func (rpcError) ErrorCode() int { return 3 }
+
+// fakeEndpoint only implements the methods used in the test
+type fakeEndpoint struct {
+	EndpointClient
+
+	mtx    sync.Mutex
+	height int64
+	err    error // returned by all the calls
+	calls  int
+}
+
+func (f *fakeEndpoint) set(height int64, err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.height, f.err = height, err
+}
+
+func (f *fakeEndpoint) GeLatestBlockHeight() (int64, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.calls++
+	return f.height, f.err
+}
+
+func (f *fakeEndpoint) GetSyncBlock(height uint64) (*modbtypes.ExtendedBlock, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &modbtypes.ExtendedBlock{Block: modbtypes.Block{Height: int64(height)}}, nil
+}
+
+func (f *fakeEndpoint) TransactionReceipt(ctx context.Context, txHash gethcmn.Hash) (*gethtypes.Receipt, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.calls++
+	return nil, f.err
+}
+
+func (f *fakeEndpoint) getCalls() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	calls := f.calls
+	f.calls = 0
+	return calls
+}
+
+func TestMultiClient(t *testing.T) {
+	endpoints := map[string]*fakeEndpoint{
+		"http://a": {height: 100},
+		"http://b": {height: 100},
+		"http://c": {height: 100},
+	}
+	a, b, c := endpoints["http://a"], endpoints["http://b"], endpoints["http://c"]
+	client, err := newMultiClient([]string{"http://a", "http://b", "http://c"}, func(url string) (EndpointClient, error) {
+		return endpoints[url], nil
+	}, tmlog.NewNopLogger())
+	require.NoError(t, err)
+
+	// the first endpoint is preferred
+	client.checkHealth()
+	blk, err := client.GetSyncBlock(100)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, blk.Height)
+	require.Equal(t, []int{2, 1, 1}, []int{a.getCalls(), b.getCalls(), c.getCalls()})
+
+	// fails over to the next endpoint, and the failed one is skipped afterwards
+	a.set(100, errors.New("connection refused"))
+	_, err = client.GetSyncBlock(100)
+	require.NoError(t, err)
+	_, err = client.GetSyncBlock(100)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 0}, []int{a.getCalls(), b.getCalls(), c.getCalls()})
+
+	// the lagging endpoint is skipped
+	b.set(90, nil)
+	client.checkHealth()
+	_, err = client.GetSyncBlock(100)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 1, 2}, []int{a.getCalls(), b.getCalls(), c.getCalls()})
+
+	// the errors returned by the node are not failed over
+	c.set(100, rpcError{})
+	_, err = client.TransactionReceipt(context.Background(), gethcmn.Hash{})
+	require.ErrorIs(t, err, rpcError{})
+	c.set(100, ethereum.NotFound)
+	_, err = client.TransactionReceipt(context.Background(), gethcmn.Hash{})
+	require.ErrorIs(t, err, ethereum.NotFound)
+	require.Equal(t, []int{0, 0, 2}, []int{a.getCalls(), b.getCalls(), c.getCalls()})
+
+	// recovered by the health check
+	a.set(101, nil)
+	c.set(100, nil)
+	client.checkHealth()
+	_, err = client.GetSyncBlock(101)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 1, 1}, []int{a.getCalls(), b.getCalls(), c.getCalls()})
+
+	stats := client.GetEndpointStats()
+	require.Len(t, stats, 3)
+	require.Equal(t, "http://a", stats[0].Url)
+	require.True(t, stats[0].Healthy)
+	require.EqualValues(t, 101, stats[0].Height)
+	require.EqualValues(t, 2, stats[0].Errors)
+	require.Equal(t, "connection refused", stats[0].LastError)
+	require.EqualValues(t, 0, stats[2].Errors)
+
+	// all the endpoints fail
+	for _, e := range endpoints {
+		e.set(101, errors.New("timeout"))
+	}
+	_, err = client.GetSyncBlock(101)
+	require.EqualError(t, err, "timeout")
+}
+
+func TestMultiClientContext(t *testing.T) {
+	endpoints := map[string]*fakeEndpoint{
+		"http://a": {height: 100},
+		"http://b": {height: 100},
+	}
+	a, b := endpoints["http://a"], endpoints["http://b"]
+	client, err := newMultiClient([]string{"http://a", "http://b"}, func(url string) (EndpointClient, error) {
+		return endpoints[url], nil
+	}, tmlog.NewNopLogger())
+	require.NoError(t, err)
+
+	// a cancelled call is neither failed over nor recorded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a.set(100, context.Canceled)
+	_, err = client.TransactionReceipt(ctx, gethcmn.Hash{})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, []int{1, 0}, []int{a.getCalls(), b.getCalls()})
+	stats := client.GetEndpointStats()
+	require.True(t, stats[0].Healthy)
+	require.EqualValues(t, 0, stats[0].Requests)
+
+	// an endpoint which hangs until the deadline is failed, but not failed over
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	a.set(100, context.DeadlineExceeded)
+	_, err = client.TransactionReceipt(ctx, gethcmn.Hash{})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, []int{1, 0}, []int{a.getCalls(), b.getCalls()})
+	stats = client.GetEndpointStats()
+	require.False(t, stats[0].Healthy)
+	require.EqualValues(t, 1, stats[0].Errors)
+
+	// the next call goes to the healthy endpoint
+	_, err = client.TransactionReceipt(context.Background(), gethcmn.Hash{})
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1}, []int{a.getCalls(), b.getCalls()})
+}
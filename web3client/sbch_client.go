@@ -6,36 +6,58 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
 	modbtypes "github.com/smartbch/moeingdb/types"
 )
 
 const (
 	ReqStrSyncBlock = `{"jsonrpc": "2.0", "method": "sbch_getSyncBlock", "params": ["%s"], "id":1}`
 	ReqStrBlockNum  = `{"jsonrpc": "2.0", "method": "eth_blockNumber", "params": [], "id":1}`
+
+	SbchRequestTimeout = 30 * time.Second
 )
 
+// an unresponsive node fails the request, so that the next endpoint is tried
+var httpClient = &http.Client{Timeout: SbchRequestTimeout}
+
 type SbchClient struct {
 	*ethclient.Client
 	url string
 }
 
 func NewSbchClient(url string) *SbchClient {
+	client, err := DialSbchClient(url)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func DialSbchClient(url string) (*SbchClient, error) {
 	if url == "" {
 		url = "http://0.0.0.0:8545"
 	}
 
-	ethClient, err := ethclient.Dial(url)
+	var rpcClient *gethrpc.Client
+	var err error
+	if strings.HasPrefix(url, "http") {
+		rpcClient, err = gethrpc.DialHTTPWithClient(url, httpClient)
+	} else {
+		rpcClient, err = gethrpc.Dial(url)
+	}
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return &SbchClient{
-		Client: ethClient,
+		Client: ethclient.NewClient(rpcClient),
 		url:    url,
-	}
+	}, nil
 }
 
 func (client *SbchClient) sendRequest(reqStr string) ([]byte, error) {
@@ -45,7 +67,7 @@ func (client *SbchClient) sendRequest(reqStr string) ([]byte, error) {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +85,18 @@ type jsonrpcError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Error and ErrorCode implement rpc.Error, the error is the answer of the node
+func (err *jsonrpcError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("json-rpc error %d", err.Code)
+	}
+	return err.Message
+}
+
+func (err *jsonrpcError) ErrorCode() int {
+	return err.Code
+}
+
 type jsonrpcMessage struct {
 	Version string          `json:"jsonrpc,omitempty"`
 	ID      json.RawMessage `json:"id,omitempty"`
@@ -83,6 +117,13 @@ func (client *SbchClient) GetSyncBlock(height uint64) (*modbtypes.ExtendedBlock,
 	if err != nil {
 		return nil, err
 	}
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	// the block is not produced yet
+	if len(m.Result) == 0 || string(m.Result) == "null" {
+		return nil, ethereum.NotFound
+	}
 	var eBlockString string
 	err = json.Unmarshal(m.Result, &eBlockString)
 	if err != nil {
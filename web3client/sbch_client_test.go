@@ -0,0 +1,57 @@
+package web3client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	modbtypes "github.com/smartbch/moeingdb/types"
+	"github.com/stretchr/testify/require"
+	tmlog "github.com/tendermint/tendermint/libs/log"
+)
+
+func TestGetSyncBlock(t *testing.T) {
+	blk := &modbtypes.ExtendedBlock{Block: modbtypes.Block{Height: 100}}
+	bz, err := blk.MarshalMsg(nil)
+	require.NoError(t, err)
+
+	var resp string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(resp))
+	}))
+	defer server.Close()
+	client, err := newMultiClient([]string{server.URL, server.URL}, func(url string) (EndpointClient, error) {
+		return DialSbchClient(url)
+	}, tmlog.NewNopLogger())
+	require.NoError(t, err)
+
+	resp = `{"jsonrpc":"2.0","id":1,"result":"` + hexutil.Encode(bz) + `"}`
+	got, err := client.GetSyncBlock(100)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, got.Height)
+
+	// the block is not produced yet
+	resp = `{"jsonrpc":"2.0","id":1,"result":null}`
+	_, err = client.GetSyncBlock(101)
+	require.ErrorIs(t, err, ethereum.NotFound)
+	resp = `{"jsonrpc":"2.0","id":1}`
+	_, err = client.GetSyncBlock(101)
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	// the error returned by the node
+	resp = `{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"block not found"}}`
+	_, err = client.GetSyncBlock(101)
+	var rpcErr gethrpc.Error
+	require.ErrorAs(t, err, &rpcErr)
+	require.Equal(t, -32000, rpcErr.ErrorCode())
+	require.EqualError(t, err, "block not found")
+
+	// the answers of the node are not failed over
+	for _, stats := range client.GetEndpointStats() {
+		require.True(t, stats.Healthy)
+		require.EqualValues(t, 0, stats.Errors)
+	}
+}
@@ -5,9 +5,16 @@ import (
 	modbtypes "github.com/smartbch/moeingdb/types"
 )
 
-type Web3Client interface {
+// EndpointClient is the client of a single smartBCH node
+type EndpointClient interface {
 	bind.DeployBackend
 	bind.ContractBackend
 	GeLatestBlockHeight() (int64, error)
 	GetSyncBlock(height uint64) (*modbtypes.ExtendedBlock, error)
 }
+
+// Web3Client is the client of the upstream smartBCH nodes
+type Web3Client interface {
+	EndpointClient
+	GetEndpointStats() []EndpointStats
+}